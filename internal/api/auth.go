@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"gameday-sim/internal/config"
+	"gameday-sim/internal/utils"
 )
 
 // TokenResponse represents the OAuth token response
@@ -22,29 +23,61 @@ type TokenResponse struct {
 	Scope        string `json:"scope,omitempty"`
 }
 
+// Token refresh triggers, used as metric keys
+const (
+	RefreshInitial      = "initial"      // First token of the run
+	RefreshExpired      = "expired"      // Cached token reached its expiry buffer
+	RefreshBackground   = "background"   // Proactive refresh by StartAutoRefresh
+	RefreshUnauthorized = "unauthorized" // The API rejected the token with a 401
+)
+
+// maxExpiryBuffer caps how long before expiry a token is considered stale
+const maxExpiryBuffer = 5 * time.Minute
+
+// autoRefreshRetry is how long the background refresher waits after a
+// failure, or before checking again on a token that does not expire
+const autoRefreshRetry = 30 * time.Second
+
+// minAutoRefreshWait keeps the background refresher from spinning on tokens
+// whose lifetime is shorter than their expiry buffer
+const minAutoRefreshWait = 5 * time.Second
+
 // AuthManager handles OAuth token generation and caching
 type AuthManager struct {
-	config      *config.OAuthConfig
-	httpClient  *http.Client
-	token       string
-	tokenExpiry time.Time
-	mu          sync.RWMutex
+	config       *config.OAuthConfig
+	httpClient   *http.Client
+	token        string
+	refreshToken string
+	tokenExpiry  time.Time // Actual expiry minus buffer; zero when the token does not expire
+	mu           sync.RWMutex
+	limiter      *RateLimiter   // Paces token requests; set by NewClient
+	metrics      *utils.Metrics // Set by Client.SetObservers
 }
 
-// NewAuthManager creates a new authentication manager
+// NewAuthManager creates a new authentication manager. The strategy is
+// chosen by cfg.GrantType (see the config.Grant* and config.Auth* constants).
 func NewAuthManager(cfg *config.OAuthConfig, timeout time.Duration) *AuthManager {
 	return &AuthManager{
-		config: cfg,
+		config:       cfg,
+		refreshToken: cfg.RefreshToken,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
 	}
 }
 
-// GetToken returns a valid token, generating a new one if needed
+// GetToken returns a valid token, generating a new one if needed. For static
+// strategies it returns the configured token or API key.
 func (am *AuthManager) GetToken(ctx context.Context) (string, error) {
+	switch am.config.Grant() {
+	case config.AuthStatic:
+		return am.config.Token, nil
+	case config.AuthAPIKey:
+		return am.config.APIKey, nil
+	}
+
 	am.mu.RLock()
-	if am.token != "" && time.Now().Before(am.tokenExpiry) {
+	if am.fresh() {
 		token := am.token
 		am.mu.RUnlock()
 		return token, nil
@@ -52,29 +85,167 @@ func (am *AuthManager) GetToken(ctx context.Context) (string, error) {
 	am.mu.RUnlock()
 
 	// Need to generate a new token
-	return am.generateToken(ctx)
+	return am.renew(ctx, "", "")
 }
 
-// generateToken requests a new OAuth token using password grant
-func (am *AuthManager) generateToken(ctx context.Context) (string, error) {
+// fresh reports whether the cached token can be used. Tokens issued without
+// expires_in do not expire on their own. Callers must hold am.mu.
+func (am *AuthManager) fresh() bool {
+	return am.token != "" && (am.tokenExpiry.IsZero() || time.Now().Before(am.tokenExpiry))
+}
+
+// Invalidate replaces a token the API rejected. Concurrent callers holding
+// the same stale token share a single re-authentication.
+func (am *AuthManager) Invalidate(ctx context.Context, stale string) (string, error) {
+	if am.config.Static() {
+		return "", fmt.Errorf("static %s credentials were rejected", am.config.Grant())
+	}
+	return am.renew(ctx, stale, RefreshUnauthorized)
+}
+
+// authorize attaches the credential to an outgoing request
+func (am *AuthManager) authorize(req *http.Request, token string) {
+	if am.config.Grant() == config.AuthAPIKey {
+		header := am.config.APIKeyHeader
+		if header == "" {
+			header = config.DefaultAPIKeyHeader
+		}
+		req.Header.Set(header, token)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// StartAutoRefresh refreshes the token in the background ahead of its expiry
+// until ctx is done
+func (am *AuthManager) StartAutoRefresh(ctx context.Context) {
+	if am.config.Static() {
+		return
+	}
+
+	go func() {
+		for {
+			am.mu.RLock()
+			token, expiry := am.token, am.tokenExpiry
+			am.mu.RUnlock()
+
+			// tokenExpiry already has the buffer taken off
+			wait := time.Until(expiry)
+			switch {
+			case token == "" || expiry.IsZero():
+				wait = autoRefreshRetry
+			case wait < minAutoRefreshWait:
+				wait = minAutoRefreshWait
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			// A token without expiry is only replaced after a 401
+			if token != "" && expiry.IsZero() {
+				continue
+			}
+
+			if _, err := am.renew(ctx, token, RefreshBackground); err != nil && ctx.Err() == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(autoRefreshRetry):
+				}
+			}
+		}
+	}()
+}
+
+// renew generates a new token unless another caller already replaced stale.
+// An empty stale renews only if the cached token has expired.
+func (am *AuthManager) renew(ctx context.Context, stale, trigger string) (string, error) {
 	am.mu.Lock()
 	defer am.mu.Unlock()
 
 	// Double-check after acquiring write lock
-	if am.token != "" && time.Now().Before(am.tokenExpiry) {
+	if am.token != stale && am.fresh() {
 		return am.token, nil
 	}
 
-	// Prepare form data for password grant
+	if trigger == "" {
+		trigger = RefreshExpired
+		if am.token == "" {
+			trigger = RefreshInitial
+		}
+	}
+
+	token, err := am.generateToken(ctx)
+	if am.metrics != nil {
+		am.metrics.RecordTokenRefresh(trigger, err == nil)
+	}
+	return token, err
+}
+
+// generateToken requests a new OAuth token, using the refresh token when one
+// is held and falling back to the configured grant. Callers must hold am.mu.
+func (am *AuthManager) generateToken(ctx context.Context) (string, error) {
+	if am.refreshToken != "" {
+		token, err := am.requestToken(ctx, am.refreshForm(am.refreshToken))
+		if err == nil || ctx.Err() != nil || am.config.Grant() == config.GrantRefreshToken {
+			return token, err
+		}
+		am.refreshToken = ""
+	}
+
+	return am.requestToken(ctx, am.grantForm())
+}
+
+// grantForm builds the token request form for the configured grant type
+func (am *AuthManager) grantForm() url.Values {
+	if am.config.Grant() == config.GrantRefreshToken {
+		return am.refreshForm(am.config.RefreshToken)
+	}
+
+	formData := am.clientForm(am.config.Grant())
+	if am.config.Grant() == config.GrantPassword {
+		formData.Set("username", am.config.Username)
+		formData.Set("password", am.config.Password)
+	}
+	return formData
+}
+
+// refreshForm builds a refresh_token grant request
+func (am *AuthManager) refreshForm(refreshToken string) url.Values {
+	formData := am.clientForm(config.GrantRefreshToken)
+	formData.Set("refresh_token", refreshToken)
+	return formData
+}
+
+// clientForm holds the fields shared by every grant: client credentials,
+// scopes and audience
+func (am *AuthManager) clientForm(grantType string) url.Values {
 	formData := url.Values{}
-	formData.Set("grant_type", am.config.GrantType)
-	formData.Set("username", am.config.Username)
-	formData.Set("password", am.config.Password)
+	formData.Set("grant_type", grantType)
 	formData.Set("client_id", am.config.ClientID)
 
 	if am.config.ClientSecret != "" {
 		formData.Set("client_secret", am.config.ClientSecret)
 	}
+	if len(am.config.Scopes) > 0 {
+		formData.Set("scope", strings.Join(am.config.Scopes, " "))
+	}
+	if am.config.Audience != "" {
+		formData.Set("audience", am.config.Audience)
+	}
+
+	return formData
+}
+
+// requestToken posts formData to the token URL and caches the result.
+// Callers must hold am.mu.
+func (am *AuthManager) requestToken(ctx context.Context, formData url.Values) (string, error) {
+	if err := waitForToken(ctx, am.limiter, EndpointToken, am.metrics); err != nil {
+		return "", err
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", am.config.TokenURL, strings.NewReader(formData.Encode()))
 	if err != nil {
@@ -104,22 +275,35 @@ func (am *AuthManager) generateToken(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("no access token in response")
 	}
 
-	// Cache the token
+	// Cache the token, treating it as expired slightly early
+	lifetime := time.Duration(tokenResp.ExpiresIn) * time.Second
 	am.token = tokenResp.AccessToken
-	// Set expiry to slightly before actual expiry (5 minutes buffer)
-	expiryDuration := time.Duration(tokenResp.ExpiresIn) * time.Second
-	if expiryDuration > 5*time.Minute {
-		expiryDuration -= 5 * time.Minute
+	am.tokenExpiry = time.Time{}
+	if lifetime > 0 {
+		am.tokenExpiry = time.Now().Add(lifetime - expiryBuffer(lifetime))
+	}
+	if tokenResp.RefreshToken != "" {
+		am.refreshToken = tokenResp.RefreshToken
 	}
-	am.tokenExpiry = time.Now().Add(expiryDuration)
 
 	return am.token, nil
 }
 
+// expiryBuffer is how long before expiry a token of the given lifetime is
+// renewed: a fifth of its lifetime, at most five minutes
+func expiryBuffer(lifetime time.Duration) time.Duration {
+	buffer := lifetime / 5
+	if buffer > maxExpiryBuffer {
+		buffer = maxExpiryBuffer
+	}
+	return buffer
+}
+
 // ClearToken clears the cached token (useful for testing or forced refresh)
 func (am *AuthManager) ClearToken() {
 	am.mu.Lock()
 	defer am.mu.Unlock()
 	am.token = ""
+	am.refreshToken = am.config.RefreshToken
 	am.tokenExpiry = time.Time{}
 }
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+func TestExecuteRequest_ReauthenticatesOn401(t *testing.T) {
+	var issued int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&issued, 1)
+		json.NewEncoder(w).Encode(TokenResponse{AccessToken: fmt.Sprintf("token-%d", n), ExpiresIn: 3600})
+	}))
+	defer tokenServer.Close()
+
+	// Only the second token is accepted
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := &config.Config{}
+	cfg.API.BaseURL = apiServer.URL
+	cfg.API.Timeout = 5 * time.Second
+	cfg.OAuth.TokenURL = tokenServer.URL
+
+	authManager := NewAuthManager(&cfg.OAuth, cfg.API.Timeout)
+	client := NewClient(cfg, authManager)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- client.doRequest(context.Background(), EndpointDetails, http.MethodGet, "/details", "", nil, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("expected request to succeed after re-authentication, got %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&issued); got != 2 {
+		t.Errorf("expected 2 tokens to be issued, got %d", got)
+	}
+}
+
+func TestExpiryBuffer(t *testing.T) {
+	tests := []struct {
+		lifetime time.Duration
+		want     time.Duration
+	}{
+		{time.Hour, 5 * time.Minute},
+		{5 * time.Minute, time.Minute},
+		{60 * time.Second, 12 * time.Second},
+		{0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := expiryBuffer(tt.lifetime); got != tt.want {
+			t.Errorf("expiryBuffer(%s) = %s, want %s", tt.lifetime, got, tt.want)
+		}
+	}
+}
+
+func TestAuthManager_GrantForms(t *testing.T) {
+	cfg := &config.OAuthConfig{
+		ClientID:     "ci",
+		ClientSecret: "secret",
+		GrantType:    config.GrantClientCredentials,
+		Scopes:       []string{"orders.read", "orders.write"},
+		Audience:     "https://api.example.com",
+	}
+	form := NewAuthManager(cfg, time.Second).grantForm()
+
+	if form.Get("grant_type") != "client_credentials" || form.Get("username") != "" {
+		t.Errorf("unexpected client_credentials form: %v", form)
+	}
+	if form.Get("scope") != "orders.read orders.write" || form.Get("audience") != "https://api.example.com" {
+		t.Errorf("expected scope and audience in form, got %v", form)
+	}
+
+	cfg = &config.OAuthConfig{ClientID: "ci", GrantType: config.GrantRefreshToken, RefreshToken: "r1"}
+	form = NewAuthManager(cfg, time.Second).grantForm()
+	if form.Get("grant_type") != "refresh_token" || form.Get("refresh_token") != "r1" {
+		t.Errorf("unexpected refresh_token form: %v", form)
+	}
+}
+
+func TestAuthManager_APIKeyHeader(t *testing.T) {
+	var got string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Key")
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := &config.Config{}
+	cfg.API.BaseURL = apiServer.URL
+	cfg.API.Timeout = 5 * time.Second
+	cfg.OAuth = config.OAuthConfig{GrantType: config.AuthAPIKey, APIKey: "k-123", APIKeyHeader: "X-Key"}
+
+	client := NewClient(cfg, NewAuthManager(&cfg.OAuth, cfg.API.Timeout))
+	if err := client.doRequest(context.Background(), EndpointDetails, http.MethodGet, "/details", "", nil, nil); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if got != "k-123" {
+		t.Errorf("expected API key header, got %q", got)
+	}
+}
+
+func TestClient_WithIdentityUsesOwnToken(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		json.NewEncoder(w).Encode(TokenResponse{AccessToken: "token-" + r.Form.Get("username"), ExpiresIn: 3600})
+	}))
+	defer tokenServer.Close()
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.Header.Get("Authorization")] = true
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	cfg := &config.Config{}
+	cfg.API.BaseURL = apiServer.URL
+	cfg.API.Timeout = 5 * time.Second
+	cfg.OAuth = config.OAuthConfig{TokenURL: tokenServer.URL, ClientID: "ci", Password: "pw"}
+	cfg.Identities.Pool = []config.Identity{
+		{Name: "a", OAuth: config.OAuthConfig{Username: "alice"}},
+		{Name: "b", OAuth: config.OAuthConfig{Username: "bob"}},
+	}
+
+	client := NewClient(cfg, NewAuthManager(&cfg.OAuth, cfg.API.Timeout))
+	for _, name := range []string{"a", "b"} {
+		ctx := WithIdentity(context.Background(), name)
+		if err := client.doRequest(ctx, EndpointDetails, http.MethodGet, "/details", "", nil, nil); err != nil {
+			t.Fatalf("request as %s failed: %v", name, err)
+		}
+	}
+
+	if !seen["Bearer token-alice"] || !seen["Bearer token-bob"] {
+		t.Errorf("expected one token per identity, saw %v", seen)
+	}
+
+	ctx := WithIdentity(context.Background(), "nobody")
+	if err := client.doRequest(ctx, EndpointDetails, http.MethodGet, "/details", "", nil, nil); err == nil {
+		t.Error("expected an error for an unknown identity")
+	}
+}
+
+func TestStartAutoRefresh_DoesNotSpin(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn int
+	}{
+		{"no expiry", 0},
+		{"lifetime within buffer", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var issued int32
+			tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&issued, 1)
+				json.NewEncoder(w).Encode(TokenResponse{AccessToken: "token", ExpiresIn: tt.expiresIn})
+			}))
+			defer tokenServer.Close()
+
+			am := NewAuthManager(&config.OAuthConfig{TokenURL: tokenServer.URL}, 5*time.Second)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if _, err := am.GetToken(ctx); err != nil {
+				t.Fatalf("GetToken: %v", err)
+			}
+			am.StartAutoRefresh(ctx)
+			time.Sleep(200 * time.Millisecond)
+
+			if got := atomic.LoadInt32(&issued); got != 1 {
+				t.Errorf("expected only the initial token request, got %d", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+// BreakerState represents the state of a circuit breaker
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // Requests flow normally
+	BreakerOpen     BreakerState = "open"      // Requests are held back
+	BreakerHalfOpen BreakerState = "half_open" // A limited number of probes test recovery
+)
+
+// breakerPollInterval bounds how long a waiting request sleeps between state checks
+const breakerPollInterval = 100 * time.Millisecond
+
+// outcome is a single recorded request result inside the error-rate window
+type outcome struct {
+	at     time.Time
+	failed bool
+}
+
+// CircuitBreaker guards a single endpoint against hammering a failing service
+type CircuitBreaker struct {
+	name     string
+	config   config.CircuitBreakerConfig
+	onChange func(name string, from, to BreakerState)
+
+	mu             sync.Mutex
+	state          BreakerState
+	consecutive    int
+	outcomes       []outcome
+	openedAt       time.Time
+	probesInFlight int
+	probeSuccesses int
+}
+
+// NewCircuitBreaker creates a closed circuit breaker. onChange, if set, is
+// called (outside the breaker's lock) on every state transition.
+func NewCircuitBreaker(name string, cfg config.CircuitBreakerConfig, onChange func(name string, from, to BreakerState)) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:     name,
+		config:   cfg.WithDefaults(),
+		onChange: onChange,
+		state:    BreakerClosed,
+	}
+}
+
+// State returns the current breaker state
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	state, from := b.refresh()
+	b.mu.Unlock()
+
+	b.notify(from, state)
+	return state
+}
+
+// Wait blocks until the breaker lets a request through or ctx is done.
+// Every successful Wait must be followed by exactly one Record call.
+func (b *CircuitBreaker) Wait(ctx context.Context) error {
+	for {
+		allowed, retryIn := b.acquire()
+		if allowed {
+			return nil
+		}
+
+		if retryIn <= 0 || retryIn > breakerPollInterval {
+			retryIn = breakerPollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryIn):
+		}
+	}
+}
+
+// Record reports the outcome of a request admitted by Wait
+func (b *CircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+
+	from := b.state
+	now := time.Now()
+
+	switch b.state {
+	case BreakerHalfOpen:
+		if b.probesInFlight > 0 {
+			b.probesInFlight--
+		}
+		if !success {
+			b.trip(now)
+		} else {
+			b.probeSuccesses++
+			if b.probeSuccesses >= b.config.HalfOpenProbes {
+				b.reset()
+			}
+		}
+	case BreakerClosed:
+		b.outcomes = append(b.pruned(now), outcome{at: now, failed: !success})
+		if success {
+			b.consecutive = 0
+		} else {
+			b.consecutive++
+		}
+		if b.shouldTrip() {
+			b.trip(now)
+		}
+	}
+
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// Release returns a slot admitted by Wait without recording an outcome, e.g.
+// when the caller's context was cancelled mid-request
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen && b.probesInFlight > 0 {
+		b.probesInFlight--
+	}
+}
+
+// WaitNotOpen blocks while the breaker is open, without taking a probe slot
+func (b *CircuitBreaker) WaitNotOpen(ctx context.Context) error {
+	for b.State() == BreakerOpen {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(breakerPollInterval):
+		}
+	}
+	return nil
+}
+
+// acquire admits a request if the breaker allows it, otherwise returns how
+// long to wait before checking again
+func (b *CircuitBreaker) acquire() (bool, time.Duration) {
+	b.mu.Lock()
+	state, from := b.refresh()
+
+	var allowed bool
+	var retryIn time.Duration
+
+	switch state {
+	case BreakerClosed:
+		allowed = true
+	case BreakerHalfOpen:
+		if b.probesInFlight < b.config.HalfOpenProbes {
+			b.probesInFlight++
+			allowed = true
+		}
+	case BreakerOpen:
+		retryIn = time.Until(b.openedAt.Add(b.config.OpenDuration))
+	}
+	b.mu.Unlock()
+
+	b.notify(from, state)
+	return allowed, retryIn
+}
+
+// refresh moves an open breaker to half-open once the open duration has
+// elapsed. It returns the current state and the state before the call.
+// Callers must hold b.mu.
+func (b *CircuitBreaker) refresh() (BreakerState, BreakerState) {
+	from := b.state
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.config.OpenDuration {
+		b.state = BreakerHalfOpen
+		b.probesInFlight = 0
+		b.probeSuccesses = 0
+	}
+	return b.state, from
+}
+
+// shouldTrip evaluates both thresholds. Callers must hold b.mu.
+func (b *CircuitBreaker) shouldTrip() bool {
+	if b.config.ConsecutiveFailures > 0 && b.consecutive >= b.config.ConsecutiveFailures {
+		return true
+	}
+
+	if b.config.ErrorRate > 0 && len(b.outcomes) >= b.config.MinRequests {
+		failed := 0
+		for _, o := range b.outcomes {
+			if o.failed {
+				failed++
+			}
+		}
+		return float64(failed)/float64(len(b.outcomes)) >= b.config.ErrorRate
+	}
+
+	return false
+}
+
+// pruned drops outcomes older than the window. Callers must hold b.mu.
+func (b *CircuitBreaker) pruned(now time.Time) []outcome {
+	cutoff := now.Add(-b.config.Window)
+	i := 0
+	for i < len(b.outcomes) && b.outcomes[i].at.Before(cutoff) {
+		i++
+	}
+	return b.outcomes[i:]
+}
+
+// trip opens the breaker. Callers must hold b.mu.
+func (b *CircuitBreaker) trip(now time.Time) {
+	b.state = BreakerOpen
+	b.openedAt = now
+	b.probesInFlight = 0
+	b.probeSuccesses = 0
+}
+
+// reset closes the breaker and clears its history. Callers must hold b.mu.
+func (b *CircuitBreaker) reset() {
+	b.state = BreakerClosed
+	b.consecutive = 0
+	b.outcomes = nil
+}
+
+// notify reports a state transition, if any
+func (b *CircuitBreaker) notify(from, to BreakerState) {
+	if from != to && b.onChange != nil {
+		b.onChange(b.name, from, to)
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+// TestCircuitBreaker_Lifecycle tests closed -> open -> half-open -> closed transitions
+func TestCircuitBreaker_Lifecycle(t *testing.T) {
+	var transitions []BreakerState
+	breaker := NewCircuitBreaker("create", config.CircuitBreakerConfig{
+		Enabled:             true,
+		ConsecutiveFailures: 3,
+		OpenDuration:        50 * time.Millisecond,
+		HalfOpenProbes:      1,
+	}, func(name string, from, to BreakerState) {
+		transitions = append(transitions, to)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := breaker.Wait(ctx); err != nil {
+			t.Fatalf("Wait returned error while closed: %v", err)
+		}
+		breaker.Record(false)
+	}
+
+	if state := breaker.State(); state != BreakerOpen {
+		t.Fatalf("State = %s after 3 failures, expected open", state)
+	}
+
+	// While open, a short-lived context should time out waiting
+	shortCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := breaker.Wait(shortCtx); err == nil {
+		t.Error("Wait should block while the breaker is open")
+	}
+
+	// After the open duration a single probe is admitted
+	if err := breaker.Wait(ctx); err != nil {
+		t.Fatalf("Wait returned error for half-open probe: %v", err)
+	}
+	if state := breaker.State(); state != BreakerHalfOpen {
+		t.Errorf("State = %s, expected half_open", state)
+	}
+	breaker.Record(true)
+
+	if state := breaker.State(); state != BreakerClosed {
+		t.Errorf("State = %s after successful probe, expected closed", state)
+	}
+
+	expected := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(transitions) != len(expected) {
+		t.Fatalf("Transitions = %v, expected %v", transitions, expected)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("Transition %d = %s, expected %s", i, transitions[i], expected[i])
+		}
+	}
+}
+
+// TestCircuitBreaker_ErrorRate tests that the error-rate threshold trips the breaker
+func TestCircuitBreaker_ErrorRate(t *testing.T) {
+	breaker := NewCircuitBreaker("details", config.CircuitBreakerConfig{
+		Enabled:     true,
+		ErrorRate:   0.5,
+		MinRequests: 4,
+	}, nil)
+
+	outcomes := []bool{true, false, true, false}
+	for _, ok := range outcomes {
+		breaker.Wait(context.Background())
+		breaker.Record(ok)
+	}
+
+	if state := breaker.State(); state != BreakerOpen {
+		t.Errorf("State = %s at 50%% error rate, expected open", state)
+	}
+}
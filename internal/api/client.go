@@ -4,80 +4,305 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"gameday-sim/internal/config"
+	"gameday-sim/internal/utils"
 )
 
 // Client represents the API client
 type Client struct {
 	baseURL     string
 	httpClient  *http.Client
-	retryMax    int
-	backoff     time.Duration
+	retry       *retryPolicy
+	runID       string // Scopes idempotency keys to this run
 	authManager *AuthManager
+	identities  map[string]*AuthManager // Per-identity credentials, selected with WithIdentity
+	statuses    *StatusMapper
+	breakers    map[string]*CircuitBreaker
+	limiters    map[string]*RateLimiter
+	logger      *utils.Logger
+	metrics     *utils.Metrics
 }
 
 // NewClient creates a new API client with authentication
 func NewClient(cfg *config.Config, authManager *AuthManager) *Client {
-	return &Client{
+	c := &Client{
 		baseURL: cfg.API.BaseURL,
 		httpClient: &http.Client{
 			Timeout: cfg.API.Timeout,
 		},
-		retryMax:    cfg.API.RetryMax,
-		backoff:     cfg.API.RetryBackoff,
+		retry:       newRetryPolicy(cfg),
+		runID:       strconv.FormatInt(time.Now().UnixNano(), 36),
 		authManager: authManager,
+		identities:  newIdentityPool(cfg),
+		statuses:    NewStatusMapper(cfg.Statuses),
+		breakers:    make(map[string]*CircuitBreaker),
+		limiters:    newRateLimiters(cfg.API.RateLimits),
 	}
+
+	if authManager != nil {
+		authManager.limiter = c.limiters[EndpointToken]
+	}
+	for _, am := range c.identities {
+		am.limiter = c.limiters[EndpointToken]
+	}
+
+	if cfg.API.CircuitBreaker.Enabled {
+		for _, endpoint := range orderEndpoints {
+			c.breakers[endpoint] = NewCircuitBreaker(endpoint, cfg.API.CircuitBreaker, c.onBreakerChange)
+		}
+	}
+
+	return c
+}
+
+// SetObservers attaches a logger and metrics collector to the client. Either may be nil.
+func (c *Client) SetObservers(logger *utils.Logger, metrics *utils.Metrics) {
+	c.logger = logger
+	c.metrics = metrics
+	if c.authManager != nil {
+		c.authManager.metrics = metrics
+	}
+	for _, am := range c.identities {
+		am.metrics = metrics
+	}
+}
+
+// BreakerStates returns the current state of every endpoint's circuit breaker
+func (c *Client) BreakerStates() map[string]BreakerState {
+	states := make(map[string]BreakerState, len(c.breakers))
+	for endpoint, breaker := range c.breakers {
+		states[endpoint] = breaker.State()
+	}
+	return states
+}
+
+// onBreakerChange logs and records circuit breaker transitions
+func (c *Client) onBreakerChange(endpoint string, from, to BreakerState) {
+	if c.logger != nil {
+		fields := map[string]interface{}{
+			"endpoint": endpoint,
+			"from":     string(from),
+			"to":       string(to),
+		}
+		if to == BreakerOpen {
+			c.logger.Warn("Circuit breaker opened", fields)
+		} else {
+			c.logger.Info("Circuit breaker state changed", fields)
+		}
+	}
+	if c.metrics != nil {
+		c.metrics.RecordBreakerTransition(endpoint, string(from), string(to))
+	}
+}
+
+// awaitBreaker blocks until the endpoint's breaker admits a request. Creates
+// additionally pause while any other endpoint's breaker is open, so no new
+// orders are started while the service is failing.
+func (c *Client) awaitBreaker(ctx context.Context, endpoint string) (*CircuitBreaker, error) {
+	if endpoint == EndpointCreate {
+		for name, breaker := range c.breakers {
+			if name == endpoint {
+				continue
+			}
+			if err := breaker.WaitNotOpen(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	breaker, ok := c.breakers[endpoint]
+	if !ok {
+		return nil, nil
+	}
+	if err := breaker.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return breaker, nil
+}
+
+// awaitRateLimit blocks until the endpoint's limiter grants a token and
+// records the time spent waiting
+func (c *Client) awaitRateLimit(ctx context.Context, endpoint string) error {
+	return waitForToken(ctx, c.limiters[endpoint], endpoint, c.metrics)
+}
+
+// waitForToken waits on limiter (if any) and records the wait in metrics
+func waitForToken(ctx context.Context, limiter *RateLimiter, endpoint string, metrics *utils.Metrics) error {
+	if limiter == nil {
+		return nil
+	}
+
+	waited, err := limiter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	if metrics != nil {
+		metrics.RecordRateLimitWait(endpoint, waited)
+	}
+	return nil
+}
+
+// recordOutcome feeds a request result to the breaker and metrics
+func (c *Client) recordOutcome(ctx context.Context, endpoint string, breaker *CircuitBreaker, err error, duration time.Duration) {
+	if c.metrics != nil {
+		c.metrics.RecordAPICall(endpoint, err == nil, duration)
+	}
+
+	if breaker == nil {
+		return
+	}
+	if ctx.Err() != nil {
+		breaker.Release()
+		return
+	}
+	// Client errors mean the service is up and answering
+	breaker.Record(err == nil || IsClientError(err))
+}
+
+// IsClientError reports whether err is a 4xx response other than 429, meaning
+// the service answered and refused the request itself
+func IsClientError(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 400 && httpErr.StatusCode < 500 && httpErr.StatusCode != http.StatusTooManyRequests
+	}
+	return false
+}
+
+// ResolveStatus maps a server-reported order status using the configured vocabulary
+func (c *Client) ResolveStatus(status string) StatusInfo {
+	return c.statuses.Resolve(status)
 }
 
-// doRequest executes an HTTP request with retry logic
-func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, target interface{}) error {
+// doRequest executes an HTTP request with retry logic. endpoint names the
+// API for circuit breaking, rate limiting, retry rules and metrics (see the
+// Endpoint constants). A non-empty idempotencyKey is sent as the
+// Idempotency-Key header and makes non-idempotent requests safe to retry.
+func (c *Client) doRequest(ctx context.Context, endpoint, method, path, idempotencyKey string, body interface{}, target interface{}) error {
+	if _, err := c.authFor(ctx); err != nil {
+		return err
+	}
+
 	var lastErr error
+	attempts := 0
+	maxRetries := c.retry.maxRetries(endpoint)
 
-	for attempt := 0; attempt <= c.retryMax; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff
-			waitTime := c.backoff * time.Duration(1<<uint(attempt-1))
+			// Waiting past the context deadline would only end in ctx.Err()
+			wait := c.retry.delay(attempt, lastErr)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+				break
+			}
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
-			case <-time.After(waitTime):
+			case <-time.After(wait):
 			}
 		}
 
-		err := c.executeRequest(ctx, method, path, body, target)
+		breaker, err := c.awaitBreaker(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+		if err := c.awaitRateLimit(ctx, endpoint); err != nil {
+			if breaker != nil {
+				breaker.Release()
+			}
+			return err
+		}
+
+		start := time.Now()
+		attempts++
+		err = c.executeRequest(ctx, method, path, idempotencyKey, body, target)
+		c.recordOutcome(ctx, endpoint, breaker, err, time.Since(start))
 		if err == nil {
 			return nil
 		}
 
 		lastErr = err
+		if ctx.Err() != nil {
+			return err
+		}
 
-		// Don't retry on client errors (4xx) except 429
-		if httpErr, ok := err.(*HTTPError); ok {
-			if httpErr.StatusCode >= 400 && httpErr.StatusCode < 500 && httpErr.StatusCode != 429 {
+		retry, reason := c.retry.shouldRetry(endpoint, err, idempotencyKey != "")
+		if !retry {
+			if attempt == 0 {
 				return err
 			}
+			break
+		}
+		if attempt < maxRetries && c.metrics != nil {
+			c.metrics.RecordRetry(endpoint, reason)
 		}
 	}
 
-	return fmt.Errorf("request failed after %d attempts: %w", c.retryMax+1, lastErr)
+	return fmt.Errorf("request failed after %d attempts: %w", attempts, lastErr)
 }
 
-// executeRequest performs a single HTTP request
-func (c *Client) executeRequest(ctx context.Context, method, path string, body interface{}, target interface{}) error {
-	url := c.baseURL + path
+// idempotencyKey derives the Idempotency-Key for an order create, or "" when
+// idempotency keys are disabled
+func (c *Client) idempotencyKey(orderNumber string) string {
+	if !c.retry.idempotencyKeys {
+		return ""
+	}
+	return "gameday-" + c.runID + "-" + orderNumber
+}
 
-	var reqBody io.Reader
+// executeRequest performs a single HTTP request. A 401 invalidates the auth
+// token and the request is sent once more with a fresh one.
+func (c *Client) executeRequest(ctx context.Context, method, path, idempotencyKey string, body interface{}, target interface{}) error {
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		data, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+		jsonData = data
+	}
+
+	am, err := c.authFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	var token string
+	if am != nil {
+		t, err := am.GetToken(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get auth token: %w", err)
+		}
+		token = t
+	}
+
+	err = c.send(ctx, am, method, path, idempotencyKey, token, jsonData, target)
+
+	var httpErr *HTTPError
+	if am != nil && errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnauthorized {
+		token, authErr := am.Invalidate(ctx, token)
+		if authErr != nil {
+			return fmt.Errorf("failed to re-authenticate after 401: %w", authErr)
+		}
+		err = c.send(ctx, am, method, path, idempotencyKey, token, jsonData, target)
+	}
+
+	return err
+}
+
+// send issues the HTTP request and decodes the response into target
+func (c *Client) send(ctx context.Context, am *AuthManager, method, path, idempotencyKey, token string, jsonData []byte, target interface{}) error {
+	url := c.baseURL + path
+
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
@@ -87,14 +312,11 @@ func (c *Client) executeRequest(ctx context.Context, method, path string, body i
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-
-	// Inject authentication token
-	if c.authManager != nil {
-		token, err := c.authManager.GetToken(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get auth token: %w", err)
-		}
-		req.Header.Set("Authorization", "Bearer "+token)
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	if am != nil && token != "" {
+		am.authorize(req, token)
 	}
 
 	resp, err := c.httpClient.Do(req)
@@ -115,12 +337,14 @@ func (c *Client) executeRequest(ctx context.Context, method, path string, body i
 			return &HTTPError{
 				StatusCode: resp.StatusCode,
 				Message:    string(respBody),
+				RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
 			}
 		}
 		return &HTTPError{
 			StatusCode: resp.StatusCode,
 			Message:    errResp.Message,
 			ErrorType:  errResp.Error,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
@@ -139,6 +363,7 @@ type HTTPError struct {
 	StatusCode int
 	Message    string
 	ErrorType  string
+	RetryAfter time.Duration // Parsed Retry-After header, if any
 }
 
 func (e *HTTPError) Error() string {
@@ -7,6 +7,19 @@ import (
 	"gameday-sim/internal/payload"
 )
 
+// Endpoint names used for circuit breaking and metrics
+const (
+	EndpointCreate   = "create"
+	EndpointDetails  = "details"
+	EndpointActivate = "activate"
+	EndpointCancel   = "cancel"
+	EndpointEnd      = "end"
+	EndpointToken    = "token"
+)
+
+// orderEndpoints lists the order lifecycle endpoints served by the API base URL
+var orderEndpoints = []string{EndpointCreate, EndpointDetails, EndpointActivate, EndpointCancel, EndpointEnd}
+
 // CreateOrder calls the create order API
 func (c *Client) CreateOrder(ctx context.Context, payload payload.OrderPayload) (*CreateOrderResponse, error) {
 	req := CreateOrderRequest{
@@ -16,10 +29,13 @@ func (c *Client) CreateOrder(ctx context.Context, payload payload.OrderPayload)
 		Timestamp:    payload.Timestamp,
 		Type:         string(payload.Type),
 		CustomFields: payload.CustomFields,
+		Geometry:     payload.Geometry,
+		Departure:    payload.Departure,
+		CoordTimes:   payload.CoordTimes,
 	}
 
 	var resp CreateOrderResponse
-	err := c.doRequest(ctx, http.MethodPost, "/operation/payload", req, &resp)
+	err := c.doRequest(ctx, EndpointCreate, http.MethodPost, "/operation/payload", c.idempotencyKey(payload.OrderNumber), req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +47,7 @@ func (c *Client) CreateOrder(ctx context.Context, payload payload.OrderPayload)
 func (c *Client) GetDetails(ctx context.Context, orderID string) (*GetDetailsResponse, error) {
 	var resp GetDetailsResponse
 	path := "/details?orderId=" + orderID
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &resp)
+	err := c.doRequest(ctx, EndpointDetails, http.MethodGet, path, "", nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +62,7 @@ func (c *Client) ActivateOrder(ctx context.Context, orderID string) (*ActivateOr
 	}
 
 	var resp ActivateOrderResponse
-	err := c.doRequest(ctx, http.MethodPost, "/activate", req, &resp)
+	err := c.doRequest(ctx, EndpointActivate, http.MethodPost, "/activate", "", req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +77,7 @@ func (c *Client) CancelOrder(ctx context.Context, orderID string) (*CancelOrderR
 	}
 
 	var resp CancelOrderResponse
-	err := c.doRequest(ctx, http.MethodPost, "/cancel", req, &resp)
+	err := c.doRequest(ctx, EndpointCancel, http.MethodPost, "/cancel", "", req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +92,7 @@ func (c *Client) EndOrder(ctx context.Context, orderID string) (*EndOrderRespons
 	}
 
 	var resp EndOrderResponse
-	err := c.doRequest(ctx, http.MethodPost, "/end", req, &resp)
+	err := c.doRequest(ctx, EndpointEnd, http.MethodPost, "/end", "", req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +107,7 @@ func (c *Client) GenerateToken(ctx context.Context, orderID string) (*OauthRespo
 	}
 
 	var resp OauthResponse
-	err := c.executeRequest(ctx, http.MethodPost, "/token", req, &resp)
+	err := c.executeRequest(ctx, http.MethodPost, "/token", "", req, &resp)
 	if err != nil {
 		return nil, err
 	}
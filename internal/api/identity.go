@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"gameday-sim/internal/config"
+)
+
+// identityKey is the context key carrying the identity a request acts as
+type identityKey struct{}
+
+// WithIdentity returns a context whose requests authenticate as the named
+// identity. An empty name uses the client's default credentials.
+func WithIdentity(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, identityKey{}, name)
+}
+
+// IdentityFrom returns the identity carried by ctx, or ""
+func IdentityFrom(ctx context.Context) string {
+	name, _ := ctx.Value(identityKey{}).(string)
+	return name
+}
+
+// newIdentityPool creates one AuthManager, and so one token cache, per configured identity
+func newIdentityPool(cfg *config.Config) map[string]*AuthManager {
+	pool := make(map[string]*AuthManager, len(cfg.Identities.Pool))
+	for _, id := range cfg.Identities.Pool {
+		oauth := cfg.ResolveIdentity(id)
+		pool[id.Name] = NewAuthManager(&oauth, cfg.API.Timeout)
+	}
+	return pool
+}
+
+// authFor returns the AuthManager for the identity carried by ctx
+func (c *Client) authFor(ctx context.Context) (*AuthManager, error) {
+	name := IdentityFrom(ctx)
+	if name == "" {
+		return c.authManager, nil
+	}
+
+	am, ok := c.identities[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown identity %q", name)
+	}
+	return am, nil
+}
+
+// authManagers returns every AuthManager in use: the identity pool when one
+// is configured, otherwise the default
+func (c *Client) authManagers() []*AuthManager {
+	if len(c.identities) == 0 {
+		if c.authManager == nil {
+			return nil
+		}
+		return []*AuthManager{c.authManager}
+	}
+
+	names := c.Identities()
+	managers := make([]*AuthManager, 0, len(names))
+	for _, name := range names {
+		managers = append(managers, c.identities[name])
+	}
+	return managers
+}
+
+// Identities returns the names of the configured identities in sorted order
+func (c *Client) Identities() []string {
+	names := make([]string, 0, len(c.identities))
+	for name := range c.identities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Authenticate obtains an initial token for the default credentials, or for
+// every identity when a pool is configured
+func (c *Client) Authenticate(ctx context.Context) error {
+	if len(c.identities) == 0 {
+		if c.authManager == nil {
+			return nil
+		}
+		_, err := c.authManager.GetToken(ctx)
+		return err
+	}
+
+	for _, name := range c.Identities() {
+		if _, err := c.identities[name].GetToken(ctx); err != nil {
+			return fmt.Errorf("identity %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// StartAutoRefresh starts background token refresh for every AuthManager in use
+func (c *Client) StartAutoRefresh(ctx context.Context) {
+	for _, am := range c.authManagers() {
+		am.StartAutoRefresh(ctx)
+	}
+}
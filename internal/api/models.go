@@ -5,16 +5,21 @@ import (
 	"fmt"
 	"io"
 	"time"
+
+	"gameday-sim/internal/payload"
 )
 
 // CreateOrderRequest represents the request to create an order
 type CreateOrderRequest struct {
-	OrderNumber  string                 `json:"orderNumber"`
-	Location     string                 `json:"location"`
-	POCOrder     string                 `json:"pocOrder"`
-	Timestamp    time.Time              `json:"timestamp"`
-	Type         string                 `json:"type"`
-	CustomFields map[string]interface{} `json:"customFields,omitempty"`
+	OrderNumber  string                   `json:"orderNumber"`
+	Location     string                   `json:"location"`
+	POCOrder     string                   `json:"pocOrder"`
+	Timestamp    time.Time                `json:"timestamp"`
+	Type         string                   `json:"type"`
+	CustomFields map[string]interface{}   `json:"customFields,omitempty"`
+	Geometry     *payload.GeoJSONGeometry `json:"geometry,omitempty"`
+	Departure    *time.Time               `json:"departure,omitempty"`  // Scheduled start along the geometry
+	CoordTimes   [][]time.Time            `json:"coordTimes,omitempty"` // Time of each position under coordTimes encoding
 }
 
 // CreateOrderResponse represents the response from create order API
@@ -29,7 +34,7 @@ type CreateOrderResponse struct {
 // GetDetailsResponse represents the response from get details API
 type GetDetailsResponse struct {
 	OrderID   string                 `json:"orderId"`
-	Status    string                 `json:"status"` // "Pending", "Accepted", "Failed"
+	Status    string                 `json:"status"` // Resolved through the configured status mapping
 	Details   map[string]interface{} `json:"details,omitempty"`
 	Timestamp time.Time              `json:"timestamp"`
 }
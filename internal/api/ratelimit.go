@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+// RateLimiter is a token bucket that paces requests to a single endpoint.
+// Waiters reserve tokens in arrival order, so a burst of callers is spread
+// out evenly rather than woken all at once.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a full token bucket. A burst below 1 is treated as 1.
+func NewRateLimiter(limit config.RateLimit) *RateLimiter {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   limit.RPS,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done. It returns the time
+// spent waiting.
+func (rl *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	delay := rl.reserve()
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		rl.cancel()
+		return 0, ctx.Err()
+	case <-timer.C:
+		return delay, nil
+	}
+}
+
+// reserve takes a token, possibly going into debt, and returns how long the
+// caller must wait for it
+func (rl *RateLimiter) reserve() time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+
+	rl.tokens--
+	if rl.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was never used
+func (rl *RateLimiter) cancel() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.tokens++
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+}
+
+// newRateLimiters builds a limiter for every configured endpoint
+func newRateLimiters(limits config.RateLimitConfig) map[string]*RateLimiter {
+	limiters := make(map[string]*RateLimiter, len(limits))
+	for endpoint, limit := range limits {
+		limiters[endpoint] = NewRateLimiter(limit)
+	}
+	return limiters
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/utils"
+)
+
+// retryPolicy decides whether and when a failed request is retried
+type retryPolicy struct {
+	retryMax          int
+	backoff           time.Duration
+	maxBackoff        time.Duration
+	fullJitter        bool
+	respectRetryAfter bool
+	idempotencyKeys   bool
+	rules             map[string]config.RetryRule
+	rng               *utils.LockedRand
+}
+
+// newRetryPolicy builds the retry policy from the API configuration
+func newRetryPolicy(cfg *config.Config) *retryPolicy {
+	respectRetryAfter := true
+	if cfg.API.Retry.RespectRetryAfter != nil {
+		respectRetryAfter = *cfg.API.Retry.RespectRetryAfter
+	}
+
+	return &retryPolicy{
+		retryMax:          cfg.API.RetryMax,
+		backoff:           cfg.API.RetryBackoff,
+		maxBackoff:        cfg.API.Retry.MaxBackoff,
+		fullJitter:        cfg.API.Retry.Jitter != "none",
+		respectRetryAfter: respectRetryAfter,
+		idempotencyKeys:   cfg.API.Retry.IdempotencyKeys,
+		rules:             cfg.API.Retry.Endpoints,
+		rng:               utils.NewLockedRand(cfg.Simulation.DeriveSeed("retry")),
+	}
+}
+
+// maxRetries returns the retry budget for an endpoint
+func (rp *retryPolicy) maxRetries(endpoint string) int {
+	if rule, ok := rp.rules[endpoint]; ok && rule.MaxRetries != nil {
+		return *rule.MaxRetries
+	}
+	return rp.retryMax
+}
+
+// idempotent reports whether repeating a request to the endpoint is safe.
+// Creates are not idempotent unless an Idempotency-Key is sent.
+func (rp *retryPolicy) idempotent(endpoint string, hasKey bool) bool {
+	if rule, ok := rp.rules[endpoint]; ok && rule.Idempotent != nil {
+		return *rule.Idempotent || hasKey
+	}
+	return endpoint != EndpointCreate || hasKey
+}
+
+// shouldRetry classifies err and decides whether another attempt is allowed.
+// The returned reason is used for metrics.
+func (rp *retryPolicy) shouldRetry(endpoint string, err error, hasKey bool) (bool, string) {
+	rule := rp.rules[endpoint]
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		reason := fmt.Sprintf("http_%d", httpErr.StatusCode)
+
+		// These statuses mean the server did not process the request,
+		// so even non-idempotent calls may be repeated
+		safe := httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode == http.StatusServiceUnavailable
+		if !safe && !rp.idempotent(endpoint, hasKey) {
+			return false, reason
+		}
+
+		if len(rule.RetryStatuses) > 0 {
+			for _, status := range rule.RetryStatuses {
+				if status == httpErr.StatusCode {
+					return true, reason
+				}
+			}
+			return false, reason
+		}
+
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500, reason
+	}
+
+	reason := "network"
+	if isTimeout(err) {
+		reason = "timeout"
+	}
+
+	if rule.RetryNetworkErrors != nil && !*rule.RetryNetworkErrors {
+		return false, reason
+	}
+
+	// A request that timed out may already have been processed; only dial
+	// failures are known not to have reached the server
+	if !rp.idempotent(endpoint, hasKey) && !isDialError(err) {
+		return false, reason
+	}
+
+	return true, reason
+}
+
+// delay returns how long to wait before the given retry attempt (1-based).
+// A server-supplied Retry-After is honoured up to maxBackoff.
+func (rp *retryPolicy) delay(attempt int, err error) time.Duration {
+	var httpErr *HTTPError
+	if rp.respectRetryAfter && errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		if rp.maxBackoff > 0 && httpErr.RetryAfter > rp.maxBackoff {
+			return rp.maxBackoff
+		}
+		return httpErr.RetryAfter
+	}
+
+	wait := rp.backoff * time.Duration(1<<uint(attempt-1))
+	if rp.maxBackoff > 0 && (wait > rp.maxBackoff || wait <= 0) {
+		wait = rp.maxBackoff
+	}
+
+	if rp.fullJitter {
+		wait = time.Duration(rp.rng.Float64() * float64(wait))
+	}
+
+	return wait
+}
+
+// isTimeout reports whether err is a network or client timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isDialError reports whether err happened while establishing the connection
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.API.RetryMax = 3
+	cfg.API.RetryBackoff = time.Second
+	policy := newRetryPolicy(cfg)
+
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Err: errors.New("connection reset")}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		err      error
+		hasKey   bool
+		want     bool
+		reason   string
+	}{
+		{"details 500", EndpointDetails, &HTTPError{StatusCode: 500}, false, true, "http_500"},
+		{"details 404", EndpointDetails, &HTTPError{StatusCode: 404}, false, false, "http_404"},
+		{"create 500 without key", EndpointCreate, &HTTPError{StatusCode: 500}, false, false, "http_500"},
+		{"create 500 with key", EndpointCreate, &HTTPError{StatusCode: 500}, true, true, "http_500"},
+		{"create 429 without key", EndpointCreate, &HTTPError{StatusCode: 429}, false, true, "http_429"},
+		{"create dial error", EndpointCreate, dialErr, false, true, "network"},
+		{"create read error", EndpointCreate, readErr, false, false, "network"},
+		{"details read error", EndpointDetails, readErr, false, true, "network"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := policy.shouldRetry(tt.endpoint, tt.err, tt.hasKey)
+			if got != tt.want || reason != tt.reason {
+				t.Errorf("shouldRetry() = (%v, %q), want (%v, %q)", got, reason, tt.want, tt.reason)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.API.RetryBackoff = time.Second
+	cfg.API.Retry.MaxBackoff = 3 * time.Second
+	cfg.API.Retry.Jitter = "none"
+	policy := newRetryPolicy(cfg)
+
+	if got := policy.delay(5, errors.New("boom")); got != 3*time.Second {
+		t.Errorf("expected backoff capped at 3s, got %s", got)
+	}
+	if got := policy.delay(1, &HTTPError{StatusCode: 429, RetryAfter: 2 * time.Second}); got != 2*time.Second {
+		t.Errorf("expected Retry-After to win, got %s", got)
+	}
+	if got := policy.delay(1, &HTTPError{StatusCode: 429, RetryAfter: 7 * time.Second}); got != 3*time.Second {
+		t.Errorf("expected Retry-After capped at 3s, got %s", got)
+	}
+	if got := parseRetryAfter("12"); got != 12*time.Second {
+		t.Errorf("parseRetryAfter(\"12\") = %s", got)
+	}
+}
+
+func TestDoRequest_ReportsAttemptsMade(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.API.BaseURL = server.URL
+	cfg.API.Timeout = 5 * time.Second
+	cfg.API.RetryMax = 5
+	cfg.API.RetryBackoff = time.Millisecond
+	client := NewClient(cfg, nil)
+
+	// The 404 is not retried, so the request stops after 2 of 6 attempts
+	err := client.doRequest(context.Background(), EndpointDetails, http.MethodGet, "/details", "", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("expected failure after 2 attempts, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("expected 2 calls, got %d", got)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"strings"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/payload"
+)
+
+// StatusInfo describes how a server-reported status maps onto the order lifecycle
+type StatusInfo struct {
+	Status    string
+	State     payload.OrderState
+	Terminal  bool
+	Retryable bool
+	Failed    bool
+	Known     bool // false when the status is not present in the mapping
+}
+
+// IsAccepted reports whether the status means the order has been accepted
+func (si StatusInfo) IsAccepted() bool {
+	return si.State == payload.StateAccepted && !si.Failed
+}
+
+// defaultStatuses preserves the original "Accepted"/"Failed" behaviour when
+// no mapping is configured
+var defaultStatuses = config.StatusConfig{
+	"pending":  {State: string(payload.StateCreated), Retryable: true},
+	"accepted": {State: string(payload.StateAccepted)},
+	"failed":   {State: string(payload.StateFailed), Terminal: true, Failed: true},
+}
+
+// StatusMapper resolves server statuses to simulator order states
+type StatusMapper struct {
+	statuses map[string]StatusInfo
+}
+
+// NewStatusMapper creates a mapper from the configured statuses, layered on
+// top of the defaults
+func NewStatusMapper(cfg config.StatusConfig) *StatusMapper {
+	m := &StatusMapper{
+		statuses: make(map[string]StatusInfo, len(defaultStatuses)+len(cfg)),
+	}
+
+	for _, mappings := range []config.StatusConfig{defaultStatuses, cfg} {
+		for status, mapping := range mappings {
+			m.statuses[strings.ToLower(status)] = StatusInfo{
+				Status:    status,
+				State:     payload.OrderState(mapping.State),
+				Terminal:  mapping.Terminal,
+				Retryable: mapping.Retryable,
+				Failed:    mapping.Failed,
+				Known:     true,
+			}
+		}
+	}
+
+	return m
+}
+
+// Resolve returns the mapping for a server status. Unknown statuses are
+// treated as retryable so that polling continues.
+func (m *StatusMapper) Resolve(status string) StatusInfo {
+	if info, ok := m.statuses[strings.ToLower(strings.TrimSpace(status))]; ok {
+		info.Status = status
+		return info
+	}
+
+	return StatusInfo{
+		Status:    status,
+		State:     payload.StateCreated,
+		Retryable: true,
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gameday-sim/internal/api"
 	"gameday-sim/internal/utils"
@@ -39,69 +40,89 @@ func (c *Cleaner) CleanupByTimestamp(ctx context.Context, timestamp string) erro
 	})
 
 	// Read order IDs from file
-	orderIDs, err := readOrderIDs(opsFilePath)
+	orders, err := readTrackedOrders(opsFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to read order IDs: %w", err)
 	}
 
 	c.logger.Info("Found orders to clean up", map[string]interface{}{
-		"totalOrders": len(orderIDs),
+		"totalOrders": len(orders),
 	})
 
 	// Process each order
 	successCount := 0
+	skippedCount := 0
 	failedCount := 0
 
-	for i, orderID := range orderIDs {
+	for i, order := range orders {
 		c.logger.Info("Processing order", map[string]interface{}{
-			"orderID":  orderID,
-			"progress": fmt.Sprintf("%d/%d", i+1, len(orderIDs)),
+			"orderID":  order.OrderID,
+			"identity": order.Identity,
+			"progress": fmt.Sprintf("%d/%d", i+1, len(orders)),
 		})
 
-		if err := c.cleanupOrder(ctx, orderID); err != nil {
+		// Clean up as the identity that created the order
+		skipped, err := c.cleanupOrder(api.WithIdentity(ctx, order.Identity), order.OrderID)
+		switch {
+		case err != nil:
 			c.logger.Error("Failed to cleanup order", map[string]interface{}{
-				"orderID": orderID,
+				"orderID": order.OrderID,
 				"error":   err.Error(),
 			})
 			failedCount++
-		} else {
+		case skipped:
+			skippedCount++
+		default:
 			successCount++
 		}
 	}
 
 	c.logger.Info("Cleanup complete", map[string]interface{}{
-		"total":   len(orderIDs),
+		"total":   len(orders),
 		"success": successCount,
+		"skipped": skippedCount,
 		"failed":  failedCount,
 	})
 
 	return nil
 }
 
-// cleanupOrder handles cleanup for a single order
-func (c *Cleaner) cleanupOrder(ctx context.Context, orderID string) error {
+// cleanupOrder handles cleanup for a single order. It reports skipped=true
+// when the order is already in a terminal state and needs no action.
+func (c *Cleaner) cleanupOrder(ctx context.Context, orderID string) (bool, error) {
 	// Get order details
 	details, err := c.apiClient.GetDetails(ctx, orderID)
 	if err != nil {
-		return fmt.Errorf("failed to get order details: %w", err)
+		return false, fmt.Errorf("failed to get order details: %w", err)
 	}
 
+	status := c.apiClient.ResolveStatus(details.Status)
+
 	c.logger.Debug("Order details retrieved", map[string]interface{}{
 		"orderID": orderID,
 		"status":  details.Status,
+		"state":   string(status.State),
 	})
 
 	// Determine action based on status
-	if details.Status == "Accepted" {
+	switch {
+	case status.Terminal:
+		// Nothing left to do for orders that already finished
+		c.logger.Info("Skipping terminal order", map[string]interface{}{
+			"orderID": orderID,
+			"status":  details.Status,
+		})
+		return true, nil
+	case status.IsAccepted():
 		// Cancel accepted orders
 		c.logger.Info("Cancelling accepted order", map[string]interface{}{
 			"orderID": orderID,
 		})
 		_, err = c.apiClient.CancelOrder(ctx, orderID)
 		if err != nil {
-			return fmt.Errorf("failed to cancel order: %w", err)
+			return false, fmt.Errorf("failed to cancel order: %w", err)
 		}
-	} else {
+	default:
 		// End all other orders
 		c.logger.Info("Ending order", map[string]interface{}{
 			"orderID": orderID,
@@ -109,11 +130,11 @@ func (c *Cleaner) cleanupOrder(ctx context.Context, orderID string) error {
 		})
 		_, err = c.apiClient.EndOrder(ctx, orderID)
 		if err != nil {
-			return fmt.Errorf("failed to end order: %w", err)
+			return false, fmt.Errorf("failed to end order: %w", err)
 		}
 	}
 
-	return nil
+	return false, nil
 }
 
 // findOperationsFile searches for operations file by timestamp
@@ -140,26 +161,36 @@ func findOperationsFile(timestamp string) (string, error) {
 	return "", fmt.Errorf("operations file not found for timestamp: %s", timestamp)
 }
 
-// readOrderIDs reads all order IDs from the operations file
-func readOrderIDs(filePath string) ([]string, error) {
+// trackedOrder is a line of the operations file: an order ID and, for runs
+// with an identity pool, the identity that created it
+type trackedOrder struct {
+	OrderID  string
+	Identity string
+}
+
+// readTrackedOrders reads all tracked orders from the operations file
+func readTrackedOrders(filePath string) ([]trackedOrder, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	var orderIDs []string
+	var orders []trackedOrder
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
-		if line != "" {
-			orderIDs = append(orderIDs, line)
+		if line == "" {
+			continue
 		}
+
+		orderID, identity, _ := strings.Cut(line, "\t")
+		orders = append(orders, trackedOrder{OrderID: orderID, Identity: identity})
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	return orderIDs, nil
+	return orders, nil
 }
@@ -2,10 +2,8 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"hash/fnv"
 	"time"
-
-	"gopkg.in/yaml.v3"
 )
 
 // Config represents the complete application configuration
@@ -16,22 +14,135 @@ type Config struct {
 	API        APIConfig        `yaml:"api"`
 	OAuth      OAuthConfig      `yaml:"oauth"`
 	Cleanup    CleanupConfig    `yaml:"cleanup"`
+	Statuses   StatusConfig     `yaml:"statuses"`
+	Identities IdentityConfig   `yaml:"identities"`
 }
 
 // SimulationConfig defines simulation parameters
 type SimulationConfig struct {
-	TotalOrders     int `yaml:"totalOrders"`
-	BatchSize       int `yaml:"batchSize"`
-	ParallelBatches int `yaml:"parallelBatches"`
-	ActivatedCount  int `yaml:"activatedCount"`
+	TotalOrders     int       `yaml:"totalOrders"`
+	BatchSize       int       `yaml:"batchSize"`
+	ParallelBatches int       `yaml:"parallelBatches"`
+	ActivatedCount  int       `yaml:"activatedCount"`
+	Distribution    string    `yaml:"distribution"` // sequential, shuffled, stratified or homogeneous
+	Seed            int64     `yaml:"seed"`         // Seed for all randomness; 0 picks one at startup
+	Epoch           time.Time `yaml:"epoch"`        // Fixed payload timestamp for reproducible runs
+}
+
+// DeriveSeed returns an independent seed for a named random stream (e.g.
+// "payload", "jitter") so that each subsystem is reproducible on its own.
+// It returns 0 when no simulation seed is set.
+func (sc SimulationConfig) DeriveSeed(stream string) int64 {
+	if sc.Seed == 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s", sc.Seed, stream)
+	seed := int64(h.Sum64())
+	if seed == 0 {
+		seed = 1
+	}
+	return seed
 }
 
 // PayloadConfig defines payload generation settings
 type PayloadConfig struct {
+	File              string                 `yaml:"file"` // Baseline geometry JSON; defaults to payload/payload.json
 	Location          string                 `yaml:"location"`
 	POCOrder          string                 `yaml:"pocOrder"`
 	OrderNumberPrefix string                 `yaml:"orderNumberPrefix"`
 	CustomFields      map[string]interface{} `yaml:"customFields"`
+	Overflow          string                 `yaml:"overflow"` // What to do when the boundary holds fewer polylines than totalOrders
+	Verify            string                 `yaml:"verify"`   // Overlap check after generation: report (default), strict or off
+	Placement         PlacementConfig        `yaml:"placement"`
+	Timing            TimingConfig           `yaml:"timing"`
+	Faults            FaultConfig            `yaml:"faults"`
+	Exports           []string               `yaml:"exports"` // Formats written after the run besides the GeoJSON dump
+}
+
+// Export formats, listed in payload.exports
+const (
+	ExportKML  = "kml"  // KML document with styled placemarks
+	ExportGPX  = "gpx"  // GPX tracks, one per order
+	ExportCSV  = "csv"  // One row per feature with its geometry as WKT
+	ExportHTML = "html" // Self-contained SVG map page that works offline
+)
+
+// Timing encodings, selected by payload.timing.encoding
+const (
+	TimingOff        = "off"        // Purely spatial geometry (default)
+	TimingCoordTimes = "coordTimes" // An RFC 3339 time per position in a coordTimes list
+	TimingM          = "m"          // Unix seconds as the M value of every position
+)
+
+// TimingConfig gives each position of a geometry the time it is reached.
+// Each order departs Departure after its timestamp and moves along its
+// geometry at the speed profile.
+type TimingConfig struct {
+	Encoding  string       `yaml:"encoding"`
+	Speed     SpeedProfile `yaml:"speed"`
+	Departure Interval     `yaml:"departure"` // Offset of the departure from the order timestamp
+}
+
+// Enabled reports whether positions are timed
+func (tc TimingConfig) Enabled() bool {
+	return tc.Encoding != "" && tc.Encoding != TimingOff
+}
+
+// SpeedProfile is a trapezoidal speed profile: accelerate from standstill to
+// the cruise speed, cruise, and decelerate to a stop at the last position
+type SpeedProfile struct {
+	Cruise       float64 `yaml:"cruise"`       // Meters per second
+	Acceleration float64 `yaml:"acceleration"` // Meters per second squared; 0 reaches cruise speed at once
+	Jitter       float64 `yaml:"jitter"`       // Fraction each order's cruise speed varies by, 0 to 1
+}
+
+// Placement strategies, selected by payload.placement.strategy
+const (
+	PlacementZigzag  = "zigzag"  // Row by row crawl with staircase stacking (default)
+	PlacementHex     = "hex"     // Like zigzag, with odd rows shifted half a column and packed closer
+	PlacementRandom  = "random"  // Uniform rejection sampling inside the boundary
+	PlacementPoisson = "poisson" // Poisson-disk spread around earlier placements
+	PlacementRoute   = "route"   // Evenly spaced along the route in the payload file
+)
+
+// PlacementConfig selects how templates are positioned. MinSeparation is the
+// gap kept between the bounding boxes of random, poisson and route
+// placements; it defaults to the larger delta.
+type PlacementConfig struct {
+	Strategy      string  `yaml:"strategy"`
+	MinSeparation float64 `yaml:"minSeparation"` // In the unit of the payload delta
+	MaxAttempts   int     `yaml:"maxAttempts"`   // Random samples per order before giving up; defaults to 1000
+}
+
+// Grid reports whether the strategy lays orders out in rows, which the
+// overflow policies rely on
+func (pc PlacementConfig) Grid() bool {
+	return pc.Strategy == "" || pc.Strategy == PlacementZigzag || pc.Strategy == PlacementHex
+}
+
+// Verify modes, selected by payload.verify
+const (
+	VerifyReport = "report" // Log overlapping geometries (default)
+	VerifyStrict = "strict" // Fail generation when any geometries overlap
+	VerifyOff    = "off"
+)
+
+// Overflow policies, selected by payload.overflow
+const (
+	OverflowFail   = "fail"   // Refuse to generate (default)
+	OverflowWrap   = "wrap"   // Start another layer, offset into the gaps of the previous ones
+	OverflowShrink = "shrink" // Scale the delta down until everything fits
+	OverflowReuse  = "reuse"  // Restart from the first placement, repeating positions
+)
+
+// DataFile returns the payload data file path, falling back to DefaultPayloadFile
+func (pc PayloadConfig) DataFile() string {
+	if pc.File == "" {
+		return DefaultPayloadFile
+	}
+	return pc.File
 }
 
 // BasePolyline represents the base GeoJSON polyline coordinates
@@ -39,43 +150,225 @@ type BasePolyline struct {
 	Coordinates [][]float64 `yaml:"coordinates"`
 }
 
-// CoordinateDelta represents the offset to apply for each new order
+// CoordinateDelta represents the offset to apply for each new order. With
+// Unit "meters" the spacing is converted to degrees around the layout
+// origin using Projection, so it keeps the same ground distance anywhere.
 type CoordinateDelta struct {
-	Longitude float64 `yaml:"longitude"`
-	Latitude  float64 `yaml:"latitude"`
+	Longitude  float64 `yaml:"longitude"`
+	Latitude   float64 `yaml:"latitude"`
+	Unit       string  `yaml:"unit"`       // degrees (default) or meters
+	Projection string  `yaml:"projection"` // equirectangular (default) or utm; used with meters
 }
 
-// PolygonBoundary represents the boundary polygon for volume generation (GeoJSON format)
+// Spacing units and projections for CoordinateDelta
+const (
+	UnitDegrees               = "degrees"
+	UnitMeters                = "meters"
+	ProjectionEquirectangular = "equirectangular"
+	ProjectionUTM             = "utm"
+)
+
+// PolygonBoundary represents the boundary polygon for volume generation (GeoJSON format).
+// Coordinates holds a Polygon: the exterior ring followed by holes. A
+// MultiPolygon boundary holds its polygons in Polygons instead.
 type PolygonBoundary struct {
-	Coordinates [][][]float64 `yaml:"coordinates"`
+	Name        string          `yaml:"name"`
+	Coordinates [][][]float64   `yaml:"coordinates"`
+	Polygons    [][][][]float64 `yaml:"polygons"`
+}
+
+// AllPolygons returns every polygon of the boundary
+func (b PolygonBoundary) AllPolygons() [][][][]float64 {
+	if len(b.Coordinates) == 0 {
+		return b.Polygons
+	}
+	return append([][][][]float64{b.Coordinates}, b.Polygons...)
 }
 
-// IntervalConfig defines timing controls
+// IntervalConfig defines timing controls. Every interval may be fixed or
+// drawn from a distribution (see Interval).
 type IntervalConfig struct {
-	BetweenCreates       time.Duration `yaml:"betweenCreates"`
-	AfterCreateBeforeGet time.Duration `yaml:"afterCreateBeforeGet"`
-	BetweenGetPolls      time.Duration `yaml:"betweenGetPolls"`
-	BeforeActivate       time.Duration `yaml:"beforeActivate"`
-	BeforeCancel         time.Duration `yaml:"beforeCancel"`
-	BeforeEnd            time.Duration `yaml:"beforeEnd"`
+	BetweenCreates       Interval `yaml:"betweenCreates"`
+	AfterCreateBeforeGet Interval `yaml:"afterCreateBeforeGet"`
+	BetweenGetPolls      Interval `yaml:"betweenGetPolls"`
+	BeforeActivate       Interval `yaml:"beforeActivate"`
+	BeforeCancel         Interval `yaml:"beforeCancel"`
+	BeforeEnd            Interval `yaml:"beforeEnd"`
+	Seed                 int64    `yaml:"seed"` // Jitter seed; 0 picks a random seed
+}
+
+// named returns the intervals keyed by their YAML names
+func (ic IntervalConfig) named() map[string]Interval {
+	return map[string]Interval{
+		"betweenCreates":       ic.BetweenCreates,
+		"afterCreateBeforeGet": ic.AfterCreateBeforeGet,
+		"betweenGetPolls":      ic.BetweenGetPolls,
+		"beforeActivate":       ic.BeforeActivate,
+		"beforeCancel":         ic.BeforeCancel,
+		"beforeEnd":            ic.BeforeEnd,
+	}
 }
 
 // APIConfig defines API client settings
 type APIConfig struct {
-	BaseURL      string        `yaml:"baseUrl"`
-	Timeout      time.Duration `yaml:"timeout"`
-	RetryMax     int           `yaml:"retryMax"`
-	RetryBackoff time.Duration `yaml:"retryBackoff"`
+	BaseURL        string               `yaml:"baseUrl"`
+	Timeout        time.Duration        `yaml:"timeout"`
+	RetryMax       int                  `yaml:"retryMax"`
+	RetryBackoff   time.Duration        `yaml:"retryBackoff"`
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuitBreaker"`
+	RateLimits     RateLimitConfig      `yaml:"rateLimits"`
+	Retry          RetryConfig          `yaml:"retry"`
+}
+
+// RetryConfig refines how failed requests are retried. RetryMax and
+// RetryBackoff remain the defaults for every endpoint.
+type RetryConfig struct {
+	MaxBackoff        time.Duration        `yaml:"maxBackoff"`        // Cap for a single backoff; 0 means no cap
+	Jitter            string               `yaml:"jitter"`            // "full" (default) or "none"
+	RespectRetryAfter *bool                `yaml:"respectRetryAfter"` // Honour Retry-After on 429/503; default true
+	IdempotencyKeys   bool                 `yaml:"idempotencyKeys"`   // Send Idempotency-Key on creates
+	Endpoints         map[string]RetryRule `yaml:"endpoints"`         // Per-endpoint overrides
+}
+
+// RetryRule overrides retry behaviour for a single endpoint
+type RetryRule struct {
+	MaxRetries         *int  `yaml:"maxRetries"`         // Overrides api.retryMax
+	RetryStatuses      []int `yaml:"retryStatuses"`      // Defaults to 429 and 5xx
+	RetryNetworkErrors *bool `yaml:"retryNetworkErrors"` // Defaults to true
+	Idempotent         *bool `yaml:"idempotent"`         // Creates default to false, everything else to true
+}
+
+// RateLimitConfig holds token-bucket limits keyed by endpoint name
+// (create, details, activate, cancel, end, token)
+type RateLimitConfig map[string]RateLimit
+
+// RateLimit is a token bucket: RPS tokens are added per second, up to Burst
+type RateLimit struct {
+	RPS   float64 `yaml:"rps"`
+	Burst int     `yaml:"burst"`
 }
 
+// knownEndpoints lists the API endpoint names used in rateLimits and
+// (except token) retry.endpoints
+var knownEndpoints = map[string]bool{
+	"create":   true,
+	"details":  true,
+	"activate": true,
+	"cancel":   true,
+	"end":      true,
+	"token":    true,
+}
+
+// CircuitBreakerConfig defines the per-endpoint circuit breaker. The breaker
+// opens on either ConsecutiveFailures in a row or an ErrorRate over Window
+// (once MinRequests calls were seen), stays open for OpenDuration and then
+// lets HalfOpenProbes requests through to test recovery.
+type CircuitBreakerConfig struct {
+	Enabled             bool          `yaml:"enabled"`
+	ConsecutiveFailures int           `yaml:"consecutiveFailures"`
+	ErrorRate           float64       `yaml:"errorRate"`
+	MinRequests         int           `yaml:"minRequests"`
+	Window              time.Duration `yaml:"window"`
+	OpenDuration        time.Duration `yaml:"openDuration"`
+	HalfOpenProbes      int           `yaml:"halfOpenProbes"`
+}
+
+// WithDefaults fills unset breaker settings with sensible defaults
+func (cb CircuitBreakerConfig) WithDefaults() CircuitBreakerConfig {
+	if cb.ConsecutiveFailures == 0 && cb.ErrorRate == 0 {
+		cb.ConsecutiveFailures = 5
+	}
+	if cb.MinRequests == 0 {
+		cb.MinRequests = 10
+	}
+	if cb.Window == 0 {
+		cb.Window = time.Minute
+	}
+	if cb.OpenDuration == 0 {
+		cb.OpenDuration = 30 * time.Second
+	}
+	if cb.HalfOpenProbes == 0 {
+		cb.HalfOpenProbes = 1
+	}
+	return cb
+}
+
+// Supported authentication strategies, selected by oauth.grantType
+const (
+	GrantPassword          = "password"
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+	AuthStatic             = "static"  // Pre-issued bearer token
+	AuthAPIKey             = "api_key" // API key sent in a request header
+)
+
+// DefaultAPIKeyHeader is used when oauth.apiKeyHeader is not set
+const DefaultAPIKeyHeader = "X-API-Key"
+
 // OAuthConfig defines OAuth authentication settings
 type OAuthConfig struct {
-	TokenURL     string `yaml:"tokenUrl"`
-	Username     string `yaml:"username"`
-	Password     string `yaml:"password"`
-	ClientID     string `yaml:"clientId"`
-	ClientSecret string `yaml:"clientSecret"`
-	GrantType    string `yaml:"grantType"`
+	TokenURL     string   `yaml:"tokenUrl"`
+	Username     string   `yaml:"username"`
+	Password     string   `yaml:"password"`
+	ClientID     string   `yaml:"clientId"`
+	ClientSecret string   `yaml:"clientSecret"`
+	GrantType    string   `yaml:"grantType"`
+	Scopes       []string `yaml:"scopes"`
+	Audience     string   `yaml:"audience"`
+	RefreshToken string   `yaml:"refreshToken"` // For the refresh_token grant
+	Token        string   `yaml:"token"`        // For static bearer auth
+	APIKey       string   `yaml:"apiKey"`
+	APIKeyHeader string   `yaml:"apiKeyHeader"`
+}
+
+// Grant returns the configured grant type, defaulting to password
+func (o OAuthConfig) Grant() string {
+	if o.GrantType == "" {
+		return GrantPassword
+	}
+	return o.GrantType
+}
+
+// Static reports whether the strategy uses fixed credentials with no token endpoint
+func (o OAuthConfig) Static() bool {
+	return o.Grant() == AuthStatic || o.Grant() == AuthAPIKey
+}
+
+// validate checks that the fields required by the grant type are set
+func (o OAuthConfig) validate(path string, errs *ValidationErrors) {
+	required := map[string][]string{
+		GrantPassword:          {"tokenUrl", "username", "password", "clientId"},
+		GrantClientCredentials: {"tokenUrl", "clientId", "clientSecret"},
+		GrantRefreshToken:      {"tokenUrl", "clientId", "refreshToken"},
+		AuthStatic:             {"token"},
+		AuthAPIKey:             {"apiKey"},
+	}
+
+	fields, ok := required[o.Grant()]
+	if !ok {
+		errs.add(path+".grantType", "%q is not supported", o.GrantType)
+		return
+	}
+
+	values := map[string]string{
+		"tokenUrl":     o.TokenURL,
+		"username":     o.Username,
+		"password":     o.Password,
+		"clientId":     o.ClientID,
+		"clientSecret": o.ClientSecret,
+		"refreshToken": o.RefreshToken,
+		"token":        o.Token,
+		"apiKey":       o.APIKey,
+	}
+	for _, field := range fields {
+		if values[field] == "" {
+			errs.add(path+"."+field, "is required for grantType %q", o.Grant())
+		}
+	}
+
+	if values["tokenUrl"] != "" && !o.Static() {
+		errs.checkURL(path+".tokenUrl", o.TokenURL)
+	}
 }
 
 // CleanupConfig defines cleanup phase settings
@@ -85,71 +378,224 @@ type CleanupConfig struct {
 	CheckInterval time.Duration `yaml:"checkInterval"`
 }
 
-// Load reads and parses the configuration file
-func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
+// StatusConfig maps server-reported order statuses (matched case-insensitively)
+// to the simulator's order states
+type StatusConfig map[string]StatusMapping
 
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
-	}
+// StatusMapping describes how a single server status should be treated
+type StatusMapping struct {
+	State     string `yaml:"state"`     // Simulator order state, e.g. "accepted", "failed"
+	Terminal  bool   `yaml:"terminal"`  // No further transitions are possible
+	Retryable bool   `yaml:"retryable"` // Keep polling while the order reports this status
+	Failed    bool   `yaml:"failed"`    // The order failed on the server side
+}
 
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
+// validOrderStates lists the order states a status may be mapped to
+var validOrderStates = map[string]bool{
+	"created":        true,
+	"accepted":       true,
+	"activated":      true,
+	"pending_cancel": true,
+	"pending_end":    true,
+	"cancelled":      true,
+	"ended":          true,
+	"failed":         true,
+}
 
-	return &cfg, nil
+// Load reads and parses the configuration file
+func Load(path string) (*Config, error) {
+	return LoadLayers(Layers{Files: []string{path}})
 }
 
-// Validate ensures configuration is valid
+// Validate ensures configuration is valid. It reports every problem at once
+// as ValidationErrors, each with the YAML path of the offending value.
 func (c *Config) Validate() error {
+	var errs ValidationErrors
+
 	if c.Simulation.TotalOrders <= 0 {
-		return fmt.Errorf("totalOrders must be positive")
+		errs.add("simulation.totalOrders", "must be positive")
 	}
 
 	if c.Simulation.BatchSize <= 0 {
-		return fmt.Errorf("batchSize must be positive")
+		errs.add("simulation.batchSize", "must be positive")
 	}
 
 	if c.Simulation.ParallelBatches <= 0 {
-		return fmt.Errorf("parallelBatches must be positive")
+		errs.add("simulation.parallelBatches", "must be positive")
 	}
 
 	if c.Simulation.ActivatedCount < 0 {
-		return fmt.Errorf("activatedCount cannot be negative")
+		errs.add("simulation.activatedCount", "cannot be negative")
 	}
 
 	if c.Simulation.ActivatedCount > c.Simulation.TotalOrders {
-		return fmt.Errorf("activatedCount (%d) cannot exceed totalOrders (%d)",
+		errs.add("simulation.activatedCount", "(%d) cannot exceed totalOrders (%d)",
 			c.Simulation.ActivatedCount, c.Simulation.TotalOrders)
 	}
 
+	switch c.Simulation.Distribution {
+	case "", "sequential", "shuffled", "stratified", "homogeneous":
+	default:
+		errs.add("simulation.distribution", "unknown distribution strategy %q", c.Simulation.Distribution)
+	}
+
+	switch c.Payload.Overflow {
+	case "", OverflowFail, OverflowWrap, OverflowShrink, OverflowReuse:
+	default:
+		errs.add("payload.overflow", "must be one of fail, wrap, shrink or reuse, got %q", c.Payload.Overflow)
+	}
+
+	switch c.Payload.Placement.Strategy {
+	case "", PlacementZigzag, PlacementHex, PlacementRandom, PlacementPoisson, PlacementRoute:
+		if !c.Payload.Placement.Grid() && c.Payload.Overflow != "" && c.Payload.Overflow != OverflowFail {
+			errs.add("payload.overflow", "%s only applies to the zigzag and hex strategies", c.Payload.Overflow)
+		}
+	default:
+		errs.add("payload.placement.strategy", "unknown placement strategy %q", c.Payload.Placement.Strategy)
+	}
+
+	if c.Payload.Placement.MinSeparation < 0 {
+		errs.add("payload.placement.minSeparation", "cannot be negative")
+	}
+
+	if c.Payload.Placement.MaxAttempts < 0 {
+		errs.add("payload.placement.maxAttempts", "cannot be negative")
+	}
+
+	switch c.Payload.Verify {
+	case "", VerifyReport, VerifyOff:
+	case VerifyStrict:
+		if c.Payload.Overflow == OverflowReuse {
+			errs.add("payload.verify", "strict cannot be combined with overflow reuse, which repeats positions")
+		}
+	default:
+		errs.add("payload.verify", "must be one of report, strict or off, got %q", c.Payload.Verify)
+	}
+
+	switch timing := c.Payload.Timing; timing.Encoding {
+	case "", TimingOff:
+	case TimingCoordTimes, TimingM:
+		if timing.Speed.Cruise <= 0 {
+			errs.add("payload.timing.speed.cruise", "must be positive")
+		}
+		if timing.Speed.Acceleration < 0 {
+			errs.add("payload.timing.speed.acceleration", "cannot be negative")
+		}
+		if timing.Speed.Jitter < 0 || timing.Speed.Jitter >= 1 {
+			errs.add("payload.timing.speed.jitter", "must be at least 0 and below 1")
+		}
+		if err := timing.Departure.Validate(); err != nil {
+			errs.add("payload.timing.departure", "%v", err)
+		}
+	default:
+		errs.add("payload.timing.encoding", "must be one of off, coordTimes or m, got %q", timing.Encoding)
+	}
+
+	c.validateFaults(&errs)
+
+	for i, format := range c.Payload.Exports {
+		switch format {
+		case ExportKML, ExportGPX, ExportCSV, ExportHTML:
+		default:
+			errs.add(fmt.Sprintf("payload.exports[%d]", i), "must be one of kml, gpx, csv or html, got %q", format)
+		}
+	}
+
 	if c.API.BaseURL == "" {
-		return fmt.Errorf("API baseUrl is required")
+		errs.add("api.baseUrl", "is required")
+	} else {
+		errs.checkURL("api.baseUrl", c.API.BaseURL)
 	}
 
 	if c.API.Timeout <= 0 {
-		return fmt.Errorf("API timeout must be positive")
+		errs.add("api.timeout", "must be positive")
+	}
+
+	if c.API.RetryMax < 0 {
+		errs.add("api.retryMax", "cannot be negative")
+	}
+
+	if c.API.RetryBackoff < 0 {
+		errs.add("api.retryBackoff", "cannot be negative")
+	}
+
+	if cb := c.API.CircuitBreaker; cb.Enabled {
+		if cb.ErrorRate < 0 || cb.ErrorRate > 1 {
+			errs.add("api.circuitBreaker.errorRate", "must be between 0 and 1")
+		}
+		if cb.ConsecutiveFailures < 0 || cb.MinRequests < 0 || cb.HalfOpenProbes < 0 {
+			errs.add("api.circuitBreaker", "thresholds cannot be negative")
+		}
+		if cb.Window < 0 || cb.OpenDuration < 0 {
+			errs.add("api.circuitBreaker", "durations cannot be negative")
+		}
+	}
+
+	for _, endpoint := range sortedKeys(c.API.RateLimits) {
+		limit := c.API.RateLimits[endpoint]
+		path := "api.rateLimits." + endpoint
+		if !knownEndpoints[endpoint] {
+			errs.add(path, "unknown endpoint")
+			continue
+		}
+		if limit.RPS <= 0 {
+			errs.add(path+".rps", "must be positive")
+		}
+		if limit.Burst < 0 {
+			errs.add(path+".burst", "cannot be negative")
+		}
 	}
 
-	if c.OAuth.TokenURL == "" {
-		return fmt.Errorf("OAuth tokenUrl is required")
+	switch c.API.Retry.Jitter {
+	case "", "full", "none":
+	default:
+		errs.add("api.retry.jitter", "must be \"full\" or \"none\", got %q", c.API.Retry.Jitter)
 	}
 
-	if c.OAuth.Username == "" {
-		return fmt.Errorf("OAuth username is required")
+	if c.API.Retry.MaxBackoff < 0 {
+		errs.add("api.retry.maxBackoff", "cannot be negative")
+	}
+
+	for _, endpoint := range sortedKeys(c.API.Retry.Endpoints) {
+		rule := c.API.Retry.Endpoints[endpoint]
+		path := "api.retry.endpoints." + endpoint
+		if !knownEndpoints[endpoint] || endpoint == "token" {
+			errs.add(path, "unknown endpoint")
+			continue
+		}
+		if rule.MaxRetries != nil && *rule.MaxRetries < 0 {
+			errs.add(path+".maxRetries", "cannot be negative")
+		}
+	}
+
+	// With an identity pool the top-level block only supplies shared defaults
+	if len(c.Identities.Pool) == 0 {
+		c.OAuth.validate("oauth", &errs)
+	}
+
+	c.Identities.validate(c.OAuth, &errs)
+
+	named := c.Intervals.named()
+	for _, name := range sortedKeys(named) {
+		if err := named[name].Validate(); err != nil {
+			errs.add("intervals."+name, "%v", err)
+		}
 	}
 
-	if c.OAuth.Password == "" {
-		return fmt.Errorf("OAuth password is required")
+	if c.Cleanup.CancelTimeout < 0 || c.Cleanup.EndTimeout < 0 || c.Cleanup.CheckInterval < 0 {
+		errs.add("cleanup", "durations cannot be negative")
 	}
 
-	if c.OAuth.ClientID == "" {
-		return fmt.Errorf("OAuth clientId is required")
+	for _, status := range sortedKeys(c.Statuses) {
+		mapping := c.Statuses[status]
+		path := "statuses." + status
+		if !validOrderStates[mapping.State] {
+			errs.add(path+".state", "unknown state %q", mapping.State)
+		}
+		if mapping.Terminal && mapping.Retryable {
+			errs.add(path, "cannot be both terminal and retryable")
+		}
 	}
 
-	return nil
+	return errs.err()
 }
@@ -0,0 +1,94 @@
+package config
+
+// Fault kinds, the deliberately bad geometries payload.faults can inject
+const (
+	FaultOverlap            = "overlap"            // A copy of another order's geometry
+	FaultBoundary           = "boundary"           // A line leaving the boundary
+	FaultSelfIntersect      = "selfIntersect"      // A bow tie crossing itself
+	FaultDegenerate         = "degenerate"         // A LineString of a single position
+	FaultAntimeridian       = "antimeridian"       // A line crossing 180° longitude
+	FaultInvalidCoordinates = "invalidCoordinates" // A position out of longitude and latitude range
+)
+
+// FaultKinds lists every fault kind in injection order
+var FaultKinds = []string{FaultOverlap, FaultBoundary, FaultSelfIntersect, FaultDegenerate, FaultAntimeridian, FaultInvalidCoordinates}
+
+// Expected outcomes of an order with injected faults
+const (
+	OutcomeRejected = "rejected" // The create call fails with a client error
+	OutcomeFailed   = "failed"   // The order is created, then reported failed
+	OutcomeAccepted = "accepted" // The order goes through like any other
+)
+
+// DefaultFaultOutcomes is what the service is expected to do with each fault kind
+var DefaultFaultOutcomes = map[string]string{
+	FaultOverlap:            OutcomeFailed,
+	FaultBoundary:           OutcomeRejected,
+	FaultSelfIntersect:      OutcomeRejected,
+	FaultDegenerate:         OutcomeRejected,
+	FaultAntimeridian:       OutcomeAccepted,
+	FaultInvalidCoordinates: OutcomeRejected,
+}
+
+// FaultConfig replaces the geometry of a fraction of orders with
+// deliberately bad geometry, tagged with the outcome the service is expected
+// to produce so the report can confirm it reacted correctly
+type FaultConfig struct {
+	Fraction float64           `yaml:"fraction"` // Share of orders to corrupt, 0 to 1
+	Kinds    map[string]int    `yaml:"kinds"`    // Kind to weight; every kind with weight 1 when empty
+	Expect   map[string]string `yaml:"expect"`   // Kind to expected outcome, over DefaultFaultOutcomes
+}
+
+// Weights returns the weight of each fault kind in FaultKinds order
+func (fc FaultConfig) Weights() []int {
+	weights := make([]int, len(FaultKinds))
+	for i, kind := range FaultKinds {
+		if len(fc.Kinds) == 0 {
+			weights[i] = 1
+		} else {
+			weights[i] = fc.Kinds[kind]
+		}
+	}
+	return weights
+}
+
+// ExpectedOutcome returns the outcome expected for a fault kind
+func (fc FaultConfig) ExpectedOutcome(kind string) string {
+	if outcome, ok := fc.Expect[kind]; ok {
+		return outcome
+	}
+	return DefaultFaultOutcomes[kind]
+}
+
+// validateFaults checks the fraction, kinds and expected outcomes of payload.faults
+func (c *Config) validateFaults(errs *ValidationErrors) {
+	faults := c.Payload.Faults
+	if faults.Fraction < 0 || faults.Fraction > 1 {
+		errs.add("payload.faults.fraction", "must be between 0 and 1")
+	}
+
+	total := 0
+	for _, kind := range sortedKeys(faults.Kinds) {
+		if _, known := DefaultFaultOutcomes[kind]; !known {
+			errs.add("payload.faults.kinds."+kind, "unknown fault kind")
+		}
+		if faults.Kinds[kind] < 0 {
+			errs.add("payload.faults.kinds."+kind, "weight cannot be negative")
+		}
+		total += faults.Kinds[kind]
+	}
+	if len(faults.Kinds) > 0 && total == 0 {
+		errs.add("payload.faults.kinds", "at least one kind needs a positive weight")
+	}
+
+	for _, kind := range sortedKeys(faults.Expect) {
+		if _, known := DefaultFaultOutcomes[kind]; !known {
+			errs.add("payload.faults.expect."+kind, "unknown fault kind")
+		}
+		switch faults.Expect[kind] {
+		case OutcomeRejected, OutcomeFailed, OutcomeAccepted:
+		default:
+			errs.add("payload.faults.expect."+kind, "must be one of rejected, failed or accepted, got %q", faults.Expect[kind])
+		}
+	}
+}
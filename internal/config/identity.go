@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Identity assignment strategies
+const (
+	AssignRoundRobin = "round_robin"
+	AssignWeighted   = "weighted"
+)
+
+// IdentityConfig defines a pool of user identities that orders are spread
+// across. With an empty pool every order uses the top-level oauth block.
+type IdentityConfig struct {
+	Assignment string     `yaml:"assignment"` // round_robin (default) or weighted
+	File       string     `yaml:"file"`       // YAML list of identities, appended to Pool
+	Pool       []Identity `yaml:"pool"`
+}
+
+// Identity is a named set of credentials with its own token cache. Unset
+// OAuth fields are inherited from the top-level oauth block.
+type Identity struct {
+	Name   string      `yaml:"name"`
+	Weight int         `yaml:"weight"` // Used by weighted assignment; defaults to 1
+	OAuth  OAuthConfig `yaml:"oauth"`
+}
+
+// EffectiveWeight returns the identity's weight, defaulting to 1
+func (id Identity) EffectiveWeight() int {
+	if id.Weight == 0 {
+		return 1
+	}
+	return id.Weight
+}
+
+// ResolveIdentity returns the OAuth settings for an identity with the
+// top-level oauth block filled in underneath
+func (c *Config) ResolveIdentity(id Identity) OAuthConfig {
+	return c.OAuth.Merge(id.OAuth)
+}
+
+// Merge returns o with every non-empty field of override applied on top
+func (o OAuthConfig) Merge(override OAuthConfig) OAuthConfig {
+	merged := o
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+
+	set(&merged.TokenURL, override.TokenURL)
+	set(&merged.Username, override.Username)
+	set(&merged.Password, override.Password)
+	set(&merged.ClientID, override.ClientID)
+	set(&merged.ClientSecret, override.ClientSecret)
+	set(&merged.GrantType, override.GrantType)
+	set(&merged.Audience, override.Audience)
+	set(&merged.RefreshToken, override.RefreshToken)
+	set(&merged.Token, override.Token)
+	set(&merged.APIKey, override.APIKey)
+	set(&merged.APIKeyHeader, override.APIKeyHeader)
+	if len(override.Scopes) > 0 {
+		merged.Scopes = override.Scopes
+	}
+
+	return merged
+}
+
+// loadFile appends the identities listed in File to the pool
+func (ic *IdentityConfig) loadFile() error {
+	if ic.File == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(ic.File)
+	if err != nil {
+		return fmt.Errorf("failed to read identities file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse identities file: %w", err)
+	}
+	if err := expandSecrets(&doc); err != nil {
+		return fmt.Errorf("failed to resolve identities file: %w", err)
+	}
+
+	var identities []Identity
+	if err := doc.Decode(&identities); err != nil {
+		return fmt.Errorf("failed to parse identities file: %w", err)
+	}
+
+	ic.Pool = append(ic.Pool, identities...)
+	return nil
+}
+
+// validate checks identity names, weights and each identity's credentials
+func (ic IdentityConfig) validate(base OAuthConfig, errs *ValidationErrors) {
+	switch ic.Assignment {
+	case "", AssignRoundRobin, AssignWeighted:
+	default:
+		errs.add("identities.assignment", "unknown assignment %q", ic.Assignment)
+	}
+
+	seen := make(map[string]bool, len(ic.Pool))
+	for i, id := range ic.Pool {
+		path := fmt.Sprintf("identities.pool[%d]", i)
+		if id.Name == "" {
+			errs.add(path+".name", "is required")
+		} else if seen[id.Name] {
+			errs.add(path+".name", "duplicate name %q", id.Name)
+		}
+		seen[id.Name] = true
+
+		if id.Weight < 0 {
+			errs.add(path+".weight", "cannot be negative")
+		}
+		base.Merge(id.OAuth).validate(path+".oauth", errs)
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported interval distributions
+const (
+	DistributionFixed       = "fixed"
+	DistributionUniform     = "uniform"
+	DistributionNormal      = "normal"
+	DistributionExponential = "exponential"
+)
+
+// RandSource is the subset of *rand.Rand used to sample intervals
+type RandSource interface {
+	Float64() float64
+	NormFloat64() float64
+	ExpFloat64() float64
+}
+
+// Interval is a wait duration that is either fixed or drawn from a distribution.
+// In YAML it accepts a plain duration ("2s") or a mapping such as
+// {distribution: uniform, min: 1s, max: 3s}, {distribution: normal, mean: 3s, stddev: 500ms}
+// or {distribution: exponential, mean: 30s}. Min and max, when set, clamp
+// normal and exponential samples.
+type Interval struct {
+	Distribution string        `yaml:"distribution"`
+	Value        time.Duration `yaml:"value"`
+	Min          time.Duration `yaml:"min"`
+	Max          time.Duration `yaml:"max"`
+	Mean         time.Duration `yaml:"mean"`
+	StdDev       time.Duration `yaml:"stddev"`
+}
+
+// FixedInterval returns an interval that always yields d
+func FixedInterval(d time.Duration) Interval {
+	return Interval{Distribution: DistributionFixed, Value: d}
+}
+
+// UnmarshalYAML accepts either a duration scalar or a distribution mapping
+func (i *Interval) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var d time.Duration
+		if err := node.Decode(&d); err != nil {
+			return fmt.Errorf("invalid interval %q: %w", node.Value, err)
+		}
+		*i = FixedInterval(d)
+		return nil
+	}
+
+	type plain Interval
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*i = Interval(p)
+	if i.Distribution == "" {
+		i.Distribution = DistributionFixed
+	}
+
+	return nil
+}
+
+// MarshalYAML renders fixed intervals as a plain duration
+func (i Interval) MarshalYAML() (interface{}, error) {
+	if i.Distribution == "" || i.Distribution == DistributionFixed {
+		return i.Value.String(), nil
+	}
+
+	type plain Interval
+	return plain(i), nil
+}
+
+// Sample draws a duration from the interval's distribution. Results are never negative.
+func (i Interval) Sample(r RandSource) time.Duration {
+	var d time.Duration
+
+	switch i.Distribution {
+	case DistributionUniform:
+		d = i.Min + time.Duration(r.Float64()*float64(i.Max-i.Min))
+	case DistributionNormal:
+		d = i.Mean + time.Duration(r.NormFloat64()*float64(i.StdDev))
+	case DistributionExponential:
+		d = time.Duration(r.ExpFloat64() * float64(i.Mean))
+	default:
+		return i.Value
+	}
+
+	if d < i.Min {
+		d = i.Min
+	}
+	if i.Max > 0 && d > i.Max {
+		d = i.Max
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	return d
+}
+
+// Validate checks that the interval's parameters are consistent
+func (i Interval) Validate() error {
+	if i.Min < 0 || i.Max < 0 || i.Mean < 0 || i.StdDev < 0 || i.Value < 0 {
+		return fmt.Errorf("durations cannot be negative")
+	}
+
+	switch i.Distribution {
+	case "", DistributionFixed:
+	case DistributionUniform:
+		if i.Max < i.Min {
+			return fmt.Errorf("uniform max (%s) must not be less than min (%s)", i.Max, i.Min)
+		}
+	case DistributionNormal:
+		if i.Mean == 0 {
+			return fmt.Errorf("normal distribution requires a mean")
+		}
+	case DistributionExponential:
+		if i.Mean == 0 {
+			return fmt.Errorf("exponential distribution requires a mean")
+		}
+	default:
+		return fmt.Errorf("unknown distribution %q", i.Distribution)
+	}
+
+	if i.Max > 0 && i.Max < i.Min {
+		return fmt.Errorf("max (%s) must not be less than min (%s)", i.Max, i.Min)
+	}
+
+	return nil
+}
+
+// String renders the interval for logs and reports
+func (i Interval) String() string {
+	switch i.Distribution {
+	case DistributionUniform:
+		return fmt.Sprintf("uniform(%s..%s)", i.Min, i.Max)
+	case DistributionNormal:
+		return fmt.Sprintf("normal(mean=%s, stddev=%s)", i.Mean, i.StdDev)
+	case DistributionExponential:
+		return fmt.Sprintf("exponential(mean=%s)", i.Mean)
+	default:
+		return i.Value.String()
+	}
+}
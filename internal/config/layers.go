@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is loaded when no -config flag is given
+const DefaultConfigFile = "config.yaml"
+
+// DefaultPayloadFile is used when payload.file is not set
+const DefaultPayloadFile = "payload/payload.json"
+
+// Layers lists the configuration sources, lowest precedence first: files are
+// merged in order, then GAMEDAY_* environment variables, then Sets.
+type Layers struct {
+	Files []string
+	Sets  []string // key.path=value overrides, e.g. simulation.totalOrders=500
+}
+
+// Sources records where each configuration value came from, keyed by its
+// lower-cased dotted path. Values with no entry are defaults.
+type Sources map[string]string
+
+// set records the source of the value at keys
+func (s Sources) set(keys []string, source string) {
+	path := strings.ToLower(strings.Join(keys, "."))
+
+	// A new value replaces everything that was set underneath it
+	for existing := range s {
+		if strings.HasPrefix(existing, path+".") {
+			delete(s, existing)
+		}
+	}
+	s[path] = source
+}
+
+// Lookup returns the source of the value at a dotted path, or "" for defaults
+func (s Sources) Lookup(path string) string {
+	return s[strings.ToLower(path)]
+}
+
+// LoadLayers merges and validates the configuration layers
+func LoadLayers(layers Layers) (*Config, error) {
+	cfg, _, err := Resolve(layers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Resolve merges the configuration layers without validating the result and
+// reports the source of every value that was set
+func Resolve(layers Layers) (*Config, Sources, error) {
+	sources := make(Sources)
+	doc := &yaml.Node{}
+
+	for _, path := range layers.Files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		var layer yaml.Node
+		if err := yaml.Unmarshal(data, &layer); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		if len(layer.Content) == 0 {
+			continue
+		}
+
+		mergeNode(documentRoot(doc), layer.Content[0], nil, path, sources)
+	}
+
+	if err := applyEnvOverrides(doc, sources); err != nil {
+		return nil, nil, fmt.Errorf("invalid environment override: %w", err)
+	}
+
+	for _, set := range layers.Sets {
+		if err := applySet(doc, set, sources); err != nil {
+			return nil, nil, fmt.Errorf("invalid --set %q: %w", set, err)
+		}
+	}
+
+	if err := expandSecrets(doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve config values: %w", err)
+	}
+
+	var cfg Config
+	if err := doc.Decode(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if err := cfg.Identities.loadFile(); err != nil {
+		return nil, nil, err
+	}
+
+	return &cfg, sources, nil
+}
+
+// mergeNode merges src into dst. Mappings merge key by key (matched
+// case-insensitively); scalars and sequences replace what was there.
+func mergeNode(dst, src *yaml.Node, keys []string, source string, sources Sources) {
+	if src.Kind != yaml.MappingNode || (dst.Kind != yaml.MappingNode && dst.Kind != 0) {
+		*dst = *src
+		sources.set(keys, source)
+		return
+	}
+	if dst.Kind == 0 {
+		*dst = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		childKeys := append(append([]string{}, keys...), key.Value)
+
+		var existing *yaml.Node
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if strings.EqualFold(dst.Content[j].Value, key.Value) {
+				existing = dst.Content[j+1]
+				break
+			}
+		}
+
+		if existing == nil {
+			existing = &yaml.Node{}
+			dst.Content = append(dst.Content, key, existing)
+		}
+		mergeNode(existing, value, childKeys, source, sources)
+	}
+}
+
+// applySet applies a single key.path=value override
+func applySet(doc *yaml.Node, set string, sources Sources) error {
+	path, value, ok := strings.Cut(set, "=")
+	if !ok {
+		return fmt.Errorf("expected key.path=value")
+	}
+
+	keys, kind, ok := resolveKeyPath(reflect.TypeOf(Config{}), strings.Split(path, "."))
+	if !ok {
+		return fmt.Errorf("unknown key %q", path)
+	}
+
+	if err := setNode(documentRoot(doc), keys, kind, value); err != nil {
+		return err
+	}
+	sources.set(keys, "--set")
+	return nil
+}
+
+// PrintYAML renders the configuration as YAML with credentials redacted and
+// each value annotated with its source
+func (c *Config) PrintYAML(sources Sources) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(c.Redacted()); err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	annotateSources(&node, nil, sources)
+
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return "", fmt.Errorf("failed to render config: %w", err)
+	}
+	encoder.Close()
+	return out.String(), nil
+}
+
+// annotateSources adds a line comment naming the source of every value
+func annotateSources(node *yaml.Node, keys []string, sources Sources) {
+	if len(keys) > 0 {
+		if source := sources.Lookup(strings.Join(keys, ".")); source != "" {
+			node.LineComment = source
+			return
+		}
+	}
+
+	if node.Kind != yaml.MappingNode {
+		if len(keys) > 0 {
+			node.LineComment = "default"
+		}
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		annotateSources(node.Content[i+1], append(append([]string{}, keys...), node.Content[i].Value), sources)
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Path source formats, selected by paths.format or the file extension
+const (
+	PathsGeoJSON = "geojson" // FeatureCollection of LineString or MultiLineString routes
+	PathsGPX     = "gpx"     // Tracks and routes of a GPX file
+	PathsGraph   = "graph"   // Road graph that routes are computed on
+)
+
+// PathSource supplies real-looking paths for orders instead of translated
+// templates: fixed routes read from GeoJSON or GPX, or a road graph on
+// which routes between random nodes are computed
+type PathSource struct {
+	File   string
+	Format string
+	Routes [][][]float64
+	Graph  *RoadGraph
+}
+
+// RoadGraph is a network of positions joined by road segments
+type RoadGraph struct {
+	Nodes map[string][]float64 `json:"nodes"` // Node ID to [longitude, latitude]
+	Edges []RoadEdge           `json:"edges"`
+}
+
+// RoadEdge joins two nodes; it can be travelled both ways unless OneWay is set
+type RoadEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	OneWay bool   `json:"oneWay"`
+}
+
+// PathSourceJSON is the paths block of payload.json
+type PathSourceJSON struct {
+	File   string `json:"file"`
+	Format string `json:"format"`
+}
+
+// pathsFormat returns the configured format, falling back to the file extension
+func pathsFormat(ps PathSourceJSON) string {
+	if ps.Format != "" {
+		return ps.Format
+	}
+	switch strings.ToLower(filepath.Ext(ps.File)) {
+	case ".gpx":
+		return PathsGPX
+	case ".graph":
+		return PathsGraph
+	default:
+		return PathsGeoJSON
+	}
+}
+
+// loadPathSource reads the file of a paths block
+func loadPathSource(ps PathSourceJSON) (*PathSource, error) {
+	source := &PathSource{File: ps.File, Format: pathsFormat(ps)}
+
+	data, err := os.ReadFile(ps.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read paths file: %w", err)
+	}
+
+	switch source.Format {
+	case PathsGeoJSON:
+		source.Routes, err = parseGeoJSONRoutes(data)
+	case PathsGPX:
+		source.Routes, err = parseGPXRoutes(data)
+	case PathsGraph:
+		source.Graph = &RoadGraph{}
+		err = json.Unmarshal(data, source.Graph)
+	default:
+		err = fmt.Errorf("unsupported paths format %q", source.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse paths file %s: %w", ps.File, err)
+	}
+	return source, nil
+}
+
+// parseGeoJSONRoutes reads every LineString, and every line of a
+// MultiLineString, in a FeatureCollection
+func parseGeoJSONRoutes(data []byte) ([][][]float64, error) {
+	var collection struct {
+		Features []struct {
+			Geometry GeoJSONGeometry `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, err
+	}
+
+	var routes [][][]float64
+	for _, f := range collection.Features {
+		if f.Geometry.Type != GeometryLineString && f.Geometry.Type != GeometryMultiLineString {
+			continue
+		}
+		parts, err := ParseParts(f.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, parts...)
+	}
+	return routes, nil
+}
+
+// gpxPoint is a track or route point of a GPX file
+type gpxPoint struct {
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+// parseGPXRoutes reads every track segment and route of a GPX file
+func parseGPXRoutes(data []byte) ([][][]float64, error) {
+	var gpx struct {
+		Tracks []struct {
+			Segments []struct {
+				Points []gpxPoint `xml:"trkpt"`
+			} `xml:"trkseg"`
+		} `xml:"trk"`
+		Routes []struct {
+			Points []gpxPoint `xml:"rtept"`
+		} `xml:"rte"`
+	}
+	if err := xml.Unmarshal(data, &gpx); err != nil {
+		return nil, err
+	}
+
+	toLine := func(points []gpxPoint) [][]float64 {
+		line := make([][]float64, len(points))
+		for i, p := range points {
+			line[i] = []float64{p.Lon, p.Lat}
+		}
+		return line
+	}
+
+	var routes [][][]float64
+	for _, trk := range gpx.Tracks {
+		for _, seg := range trk.Segments {
+			routes = append(routes, toLine(seg.Points))
+		}
+	}
+	for _, rte := range gpx.Routes {
+		routes = append(routes, toLine(rte.Points))
+	}
+	return routes, nil
+}
+
+// checkPathSource validates the routes or graph of a path source
+func checkPathSource(ps *PathSource, errs *ValidationErrors) {
+	if ps.Graph == nil {
+		if len(ps.Routes) == 0 {
+			errs.add("paths.file", "%s has no routes", ps.File)
+		}
+		for i, route := range ps.Routes {
+			checkLine(fmt.Sprintf("paths.routes[%d]", i), route, errs)
+		}
+		return
+	}
+
+	if len(ps.Graph.Nodes) < 2 {
+		errs.add("paths.nodes", "a road graph needs at least 2 nodes")
+	}
+	if len(ps.Graph.Edges) == 0 {
+		errs.add("paths.edges", "a road graph needs at least 1 edge")
+	}
+	for _, id := range sortedKeys(ps.Graph.Nodes) {
+		checkPosition("paths.nodes."+id, ps.Graph.Nodes[id], errs)
+	}
+	for i, e := range ps.Graph.Edges {
+		path := fmt.Sprintf("paths.edges[%d]", i)
+		if _, ok := ps.Graph.Nodes[e.From]; !ok {
+			errs.add(path+".from", "unknown node %q", e.From)
+		}
+		if _, ok := ps.Graph.Nodes[e.To]; !ok {
+			errs.add(path+".to", "unknown node %q", e.To)
+		}
+	}
+}
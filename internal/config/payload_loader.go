@@ -8,9 +8,75 @@ import (
 
 // PayloadData represents the baseline payload configuration from JSON
 type PayloadData struct {
-	BasePolyline BasePolyline    `json:"basePolyline"`
-	Boundary     PolygonBoundary `json:"boundary"`
-	Delta        CoordinateDelta `json:"delta"`
+	BasePolyline BasePolyline      `json:"basePolyline"`
+	Templates    []Template        `json:"templates"` // Replaces BasePolyline when set
+	Boundary     PolygonBoundary   `json:"boundary"`
+	Boundaries   []PolygonBoundary `json:"boundaries"` // Further named areas, each may hold orders
+	Delta        CoordinateDelta   `json:"delta"`
+	Route        [][]float64       `json:"route"` // Seed route for the route placement strategy
+	Paths        *PathSource       `json:"-"`     // Replaces templates and placement when set
+}
+
+// DefaultBoundaryName names the boundary block of payload.json
+const DefaultBoundaryName = "boundary"
+
+// BoundarySet returns every configured boundary with at least one polygon
+func (pd *PayloadData) BoundarySet() []PolygonBoundary {
+	var set []PolygonBoundary
+	if len(pd.Boundary.AllPolygons()) > 0 {
+		b := pd.Boundary
+		if b.Name == "" {
+			b.Name = DefaultBoundaryName
+		}
+		set = append(set, b)
+	}
+	for _, b := range pd.Boundaries {
+		if len(b.AllPolygons()) > 0 {
+			set = append(set, b)
+		}
+	}
+	return set
+}
+
+// Geometry types supported for payload templates and boundaries
+const (
+	GeometryPoint           = "Point"
+	GeometryLineString      = "LineString"
+	GeometryPolygon         = "Polygon"
+	GeometryMultiLineString = "MultiLineString"
+	GeometryMultiPolygon    = "MultiPolygon" // Boundaries only
+)
+
+// Template is a geometry drawn for orders, placed where it sits at row 0,
+// column 0 of the layout. Parts holds a single position for a Point, the
+// positions of a LineString, the rings of a Polygon or the lines of a
+// MultiLineString.
+type Template struct {
+	Name   string
+	Weight int // Share of orders using this template; defaults to 1
+	Type   string
+	Parts  [][][]float64
+}
+
+// EffectiveWeight returns the template's weight, defaulting to 1
+func (t Template) EffectiveWeight() int {
+	if t.Weight == 0 {
+		return 1
+	}
+	return t.Weight
+}
+
+// TemplateSet returns the templates to draw from, falling back to the base
+// polyline as a single LineString template
+func (pd *PayloadData) TemplateSet() []Template {
+	if len(pd.Templates) > 0 {
+		return pd.Templates
+	}
+	return []Template{{
+		Name:  "basePolyline",
+		Type:  GeometryLineString,
+		Parts: [][][]float64{pd.BasePolyline.Coordinates},
+	}}
 }
 
 // GeoJSONLineString represents a GeoJSON LineString
@@ -19,17 +85,80 @@ type GeoJSONLineString struct {
 	Coordinates [][]float64 `json:"coordinates"`
 }
 
-// GeoJSONPolygon represents a GeoJSON Polygon
-type GeoJSONPolygon struct {
-	Type        string        `json:"type"`
-	Coordinates [][][]float64 `json:"coordinates"`
+// NamedBoundaryJSON is a named boundary in payload.json
+type NamedBoundaryJSON struct {
+	Name     string          `json:"name"`
+	Geometry GeoJSONGeometry `json:"geometry"`
+}
+
+// GeoJSONGeometry is a GeoJSON geometry whose coordinate nesting depends on its type
+type GeoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// TemplateJSON is a weighted template geometry in payload.json
+type TemplateJSON struct {
+	Name     string          `json:"name"`
+	Weight   int             `json:"weight"`
+	Geometry GeoJSONGeometry `json:"geometry"`
 }
 
 // PayloadDataJSON is the JSON structure from payload.json
 type PayloadDataJSON struct {
-	BasePolyline GeoJSONLineString `json:"basePolyline"`
-	Boundary     GeoJSONPolygon    `json:"boundary"`
-	Delta        CoordinateDelta   `json:"delta"`
+	BasePolyline GeoJSONLineString   `json:"basePolyline"`
+	Templates    []TemplateJSON      `json:"templates"`
+	Boundary     GeoJSONGeometry     `json:"boundary"` // Polygon or MultiPolygon
+	Boundaries   []NamedBoundaryJSON `json:"boundaries"`
+	Delta        CoordinateDelta     `json:"delta"`
+	Route        GeoJSONLineString   `json:"route"`
+	Paths        *PathSourceJSON     `json:"paths"`
+}
+
+// parseBoundary decodes a Polygon or MultiPolygon boundary. A missing
+// boundary yields an empty one.
+func parseBoundary(name string, g GeoJSONGeometry) (PolygonBoundary, error) {
+	boundary := PolygonBoundary{Name: name}
+	if len(g.Coordinates) == 0 {
+		return boundary, nil
+	}
+
+	switch g.Type {
+	case GeometryPolygon, "":
+		err := json.Unmarshal(g.Coordinates, &boundary.Coordinates)
+		return boundary, err
+	case GeometryMultiPolygon:
+		err := json.Unmarshal(g.Coordinates, &boundary.Polygons)
+		return boundary, err
+	default:
+		return boundary, fmt.Errorf("unsupported boundary type %q", g.Type)
+	}
+}
+
+// ParseParts decodes the coordinates of a template geometry into parts
+func ParseParts(g GeoJSONGeometry) ([][][]float64, error) {
+	switch g.Type {
+	case GeometryPoint:
+		var position []float64
+		if err := json.Unmarshal(g.Coordinates, &position); err != nil {
+			return nil, err
+		}
+		return [][][]float64{{position}}, nil
+	case GeometryLineString:
+		var line [][]float64
+		if err := json.Unmarshal(g.Coordinates, &line); err != nil {
+			return nil, err
+		}
+		return [][][]float64{line}, nil
+	case GeometryPolygon, GeometryMultiLineString:
+		var parts [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &parts); err != nil {
+			return nil, err
+		}
+		return parts, nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
 }
 
 // LoadPayloadData loads the payload configuration from JSON file
@@ -44,16 +173,229 @@ func LoadPayloadData(filePath string) (*PayloadData, error) {
 		return nil, fmt.Errorf("failed to parse payload JSON: %w", err)
 	}
 
+	boundary, err := parseBoundary("", jsonData.Boundary)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload file %s: boundary: %w", filePath, err)
+	}
+
 	// Convert to internal format
 	payloadData := &PayloadData{
 		BasePolyline: BasePolyline{
 			Coordinates: jsonData.BasePolyline.Coordinates,
 		},
-		Boundary: PolygonBoundary{
-			Coordinates: jsonData.Boundary.Coordinates,
-		},
-		Delta: jsonData.Delta,
+		Boundary: boundary,
+		Delta:    jsonData.Delta,
+		Route:    jsonData.Route.Coordinates,
+	}
+
+	for i, b := range jsonData.Boundaries {
+		named, err := parseBoundary(b.Name, b.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload file %s: boundaries[%d].geometry: %w", filePath, i, err)
+		}
+		payloadData.Boundaries = append(payloadData.Boundaries, named)
+	}
+
+	if jsonData.Paths != nil {
+		payloadData.Paths, err = loadPathSource(*jsonData.Paths)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload file %s: paths: %w", filePath, err)
+		}
+	}
+
+	for i, t := range jsonData.Templates {
+		parts, err := ParseParts(t.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload file %s: templates[%d].geometry: %w", filePath, i, err)
+		}
+		name := t.Name
+		if name == "" {
+			name = fmt.Sprintf("template-%d", i+1)
+		}
+		payloadData.Templates = append(payloadData.Templates, Template{
+			Name:   name,
+			Weight: t.Weight,
+			Type:   t.Geometry.Type,
+			Parts:  parts,
+		})
+	}
+
+	if err := ValidatePayloadData(payloadData); err != nil {
+		return nil, fmt.Errorf("invalid payload file %s: %w", filePath, err)
 	}
 
 	return payloadData, nil
 }
+
+// ValidatePayloadData checks the baseline geometry and reports every problem
+// as ValidationErrors with JSON paths
+func ValidatePayloadData(pd *PayloadData) error {
+	var errs ValidationErrors
+
+	if len(pd.Templates) == 0 && pd.Paths == nil {
+		checkLine("basePolyline.coordinates", pd.BasePolyline.Coordinates, &errs)
+	}
+
+	templateNames := make(map[string]bool)
+	for i, t := range pd.Templates {
+		path := fmt.Sprintf("templates[%d]", i)
+		if templateNames[t.Name] {
+			errs.add(path+".name", "duplicate template name %q", t.Name)
+		}
+		templateNames[t.Name] = true
+		if t.Weight < 0 {
+			errs.add(path+".weight", "cannot be negative")
+		}
+		checkTemplate(path+".geometry", t, &errs)
+	}
+
+	if pd.Delta.Longitude <= 0 {
+		errs.add("delta.longitude", "must be positive")
+	}
+	if pd.Delta.Latitude <= 0 {
+		errs.add("delta.latitude", "must be positive")
+	}
+
+	switch pd.Delta.Unit {
+	case "", UnitDegrees, UnitMeters:
+	default:
+		errs.add("delta.unit", "must be degrees or meters, got %q", pd.Delta.Unit)
+	}
+
+	switch pd.Delta.Projection {
+	case "", ProjectionEquirectangular, ProjectionUTM:
+	default:
+		errs.add("delta.projection", "must be equirectangular or utm, got %q", pd.Delta.Projection)
+	}
+
+	checkBoundary("boundary", pd.Boundary, &errs)
+
+	if len(pd.Route) > 0 {
+		checkLine("route.coordinates", pd.Route, &errs)
+	}
+
+	if pd.Paths != nil {
+		checkPathSource(pd.Paths, &errs)
+	}
+
+	names := make(map[string]bool)
+
+	for i, b := range pd.Boundaries {
+		path := fmt.Sprintf("boundaries[%d]", i)
+		switch {
+		case b.Name == "":
+			errs.add(path+".name", "is required")
+		case b.Name == DefaultBoundaryName || names[b.Name]:
+			errs.add(path+".name", "duplicate boundary name %q", b.Name)
+		}
+		names[b.Name] = true
+		if len(b.AllPolygons()) == 0 {
+			errs.add(path+".geometry", "must have at least one polygon")
+		}
+		checkBoundary(path+".geometry", b, &errs)
+	}
+
+	return errs.err()
+}
+
+// checkBoundary validates the rings of every polygon of a boundary. Paths
+// follow the GeoJSON nesting: coordinates[ring] for a Polygon and
+// coordinates[polygon][ring] for a MultiPolygon.
+func checkBoundary(path string, b PolygonBoundary, errs *ValidationErrors) {
+	for r, ring := range b.Coordinates {
+		checkRing(fmt.Sprintf("%s.coordinates[%d]", path, r), ring, errs)
+	}
+	for p, polygon := range b.Polygons {
+		if len(polygon) == 0 {
+			errs.add(fmt.Sprintf("%s.coordinates[%d]", path, p), "polygon has no rings")
+		}
+		for r, ring := range polygon {
+			checkRing(fmt.Sprintf("%s.coordinates[%d][%d]", path, p, r), ring, errs)
+		}
+	}
+}
+
+// checkTemplate validates the parts of a template against its geometry type
+func checkTemplate(path string, t Template, errs *ValidationErrors) {
+	coordinates := path + ".coordinates"
+
+	switch t.Type {
+	case GeometryPoint:
+		if len(t.Parts) != 1 || len(t.Parts[0]) != 1 {
+			errs.add(coordinates, "must be a single position")
+			return
+		}
+		checkPosition(coordinates, t.Parts[0][0], errs)
+	case GeometryLineString:
+		if len(t.Parts) != 1 {
+			errs.add(coordinates, "must be a single line")
+			return
+		}
+		checkLine(coordinates, t.Parts[0], errs)
+	case GeometryPolygon, GeometryMultiLineString:
+		if len(t.Parts) == 0 {
+			errs.add(coordinates, "must have at least one part")
+		}
+		for i, part := range t.Parts {
+			partPath := fmt.Sprintf("%s[%d]", coordinates, i)
+			if t.Type == GeometryPolygon {
+				checkRing(partPath, part, errs)
+			} else {
+				checkLine(partPath, part, errs)
+			}
+		}
+	default:
+		errs.add(path+".type", "unsupported geometry type %q", t.Type)
+	}
+}
+
+// checkLine validates a line of at least 2 positions
+func checkLine(path string, line [][]float64, errs *ValidationErrors) {
+	if len(line) < 2 {
+		errs.add(path, "must have at least 2 positions, got %d", len(line))
+	}
+	for i, position := range line {
+		checkPosition(fmt.Sprintf("%s[%d]", path, i), position, errs)
+	}
+}
+
+// checkRing validates a closed linear ring: at least 3 distinct positions plus
+// the closing one, which must equal the first
+func checkRing(path string, ring [][]float64, errs *ValidationErrors) {
+	if len(ring) < 4 {
+		errs.add(path, "must have at least 3 points plus the closing point, got %d positions", len(ring))
+	}
+
+	valid := true
+	for i, position := range ring {
+		if !checkPosition(fmt.Sprintf("%s[%d]", path, i), position, errs) {
+			valid = false
+		}
+	}
+
+	if valid && len(ring) > 0 {
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			errs.add(path, "ring is not closed: first position %v differs from last %v", first, last)
+		}
+	}
+}
+
+// checkPosition validates a [longitude, latitude] pair and its ranges
+func checkPosition(path string, position []float64, errs *ValidationErrors) bool {
+	if len(position) != 2 && len(position) != 3 {
+		errs.add(path, "must be [longitude, latitude] or [longitude, latitude, elevation], got %d values", len(position))
+		return false
+	}
+
+	ok := true
+	if position[0] < -180 || position[0] > 180 {
+		errs.add(path, "longitude %v is outside [-180, 180]", position[0])
+		ok = false
+	}
+	if position[1] < -90 || position[1] > 90 {
+		errs.add(path, "latitude %v is outside [-90, 90]", position[1])
+		ok = false
+	}
+	return ok
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts environment variables that override config keys, e.g.
+// GAMEDAY_API_BASEURL overrides api.baseUrl
+const EnvPrefix = "GAMEDAY_"
+
+// filePrefix marks a scalar whose value is read from a file, e.g. "file:/run/secrets/password"
+const filePrefix = "file:"
+
+// Redacted replaces secret values in logs and report snapshots
+const Redacted = "[REDACTED]"
+
+// envPattern matches ${VAR} and ${VAR:-default}
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandSecrets resolves ${VAR} references and file: values in every scalar
+// of the document
+func expandSecrets(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return expandScalar(node)
+	}
+
+	for _, child := range node.Content {
+		if err := expandSecrets(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandScalar resolves a single scalar node in place
+func expandScalar(node *yaml.Node) error {
+	value := node.Value
+	changed := false
+
+	if envPattern.MatchString(value) {
+		var missing []string
+		value = envPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			m := envPattern.FindStringSubmatch(ref)
+			if v, ok := os.LookupEnv(m[1]); ok {
+				return v
+			}
+			if m[2] != "" {
+				return m[3]
+			}
+			missing = append(missing, m[1])
+			return ""
+		})
+		if len(missing) > 0 {
+			return fmt.Errorf("line %d: environment variable %s is not set", node.Line, strings.Join(missing, ", "))
+		}
+		changed = true
+	}
+
+	if strings.HasPrefix(value, filePrefix) {
+		data, err := os.ReadFile(strings.TrimPrefix(value, filePrefix))
+		if err != nil {
+			return fmt.Errorf("line %d: failed to read secret file: %w", node.Line, err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+		changed = true
+	}
+
+	if changed {
+		node.Value = value
+		// Let plain scalars re-resolve their type, so ${COUNT} can fill an int
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+	}
+	return nil
+}
+
+// applyEnvOverrides sets config keys from GAMEDAY_* environment variables.
+// Path segments are separated by underscores and matched case-insensitively
+// against the YAML keys; variables that match no key are ignored.
+func applyEnvOverrides(doc *yaml.Node, sources Sources) error {
+	root := documentRoot(doc)
+
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+
+		segments := strings.Split(strings.TrimPrefix(name, EnvPrefix), "_")
+		keys, kind, ok := resolveKeyPath(reflect.TypeOf(Config{}), segments)
+		if !ok {
+			continue
+		}
+
+		if err := setNode(root, keys, kind, value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		sources.set(keys, "env "+name)
+	}
+	return nil
+}
+
+// documentRoot returns the top-level mapping of a document, creating it for empty files
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	return doc.Content[0]
+}
+
+// resolveKeyPath maps underscore-separated segments to YAML keys by walking
+// the yaml tags of t. Map keys are taken as-is in lower case.
+func resolveKeyPath(t reflect.Type, segments []string) ([]string, reflect.Kind, bool) {
+	keys := make([]string, 0, len(segments))
+
+	for _, segment := range segments {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			field, key, ok := fieldByYAMLKey(t, segment)
+			if !ok {
+				return nil, 0, false
+			}
+			keys = append(keys, key)
+			t = field.Type
+		case reflect.Map:
+			keys = append(keys, strings.ToLower(segment))
+			t = t.Elem()
+		default:
+			return nil, 0, false
+		}
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return keys, t.Kind(), len(keys) > 0
+}
+
+// fieldByYAMLKey finds the struct field whose yaml key matches name case-insensitively
+func fieldByYAMLKey(t reflect.Type, name string) (reflect.StructField, string, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if key != "" && key != "-" && strings.EqualFold(key, name) {
+			return field, key, true
+		}
+	}
+	return reflect.StructField{}, "", false
+}
+
+// setNode stores value at keys, creating intermediate mappings. Values for
+// lists, maps and structs are parsed as YAML; everything else is a plain scalar.
+func setNode(node *yaml.Node, keys []string, kind reflect.Kind, value string) error {
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", strings.Join(keys[:i], "."))
+		}
+
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if strings.EqualFold(node.Content[j].Value, key) {
+				child = node.Content[j+1]
+				break
+			}
+		}
+
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+		}
+		node = child
+	}
+
+	switch kind {
+	case reflect.Slice, reflect.Map, reflect.Struct:
+		var parsed yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+			return fmt.Errorf("invalid YAML value: %w", err)
+		}
+		if len(parsed.Content) > 0 {
+			*node = *parsed.Content[0]
+		}
+	default:
+		*node = yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	}
+	return nil
+}
+
+// Secrets returns every credential in the configuration, for log redaction
+func (c *Config) Secrets() []string {
+	oauths := []OAuthConfig{c.OAuth}
+	for _, id := range c.Identities.Pool {
+		oauths = append(oauths, id.OAuth)
+	}
+
+	var secrets []string
+	for _, o := range oauths {
+		for _, s := range []string{o.Password, o.ClientSecret, o.RefreshToken, o.Token, o.APIKey} {
+			if s != "" {
+				secrets = append(secrets, s)
+			}
+		}
+	}
+	return secrets
+}
+
+// Redacted returns a copy of the configuration with credentials masked,
+// safe to include in logs and reports
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.OAuth = c.OAuth.redacted()
+
+	redacted.Identities.Pool = make([]Identity, len(c.Identities.Pool))
+	for i, id := range c.Identities.Pool {
+		id.OAuth = id.OAuth.redacted()
+		redacted.Identities.Pool[i] = id
+	}
+	return &redacted
+}
+
+// redacted masks every credential field that is set
+func (o OAuthConfig) redacted() OAuthConfig {
+	mask := func(s *string) {
+		if *s != "" {
+			*s = Redacted
+		}
+	}
+
+	mask(&o.Password)
+	mask(&o.ClientSecret)
+	mask(&o.RefreshToken)
+	mask(&o.Token)
+	mask(&o.APIKey)
+	return o
+}
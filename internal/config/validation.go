@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// FieldError is a single validation problem at a YAML or JSON path
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors collects every problem found while validating, so they
+// can be reported at once
+type ValidationErrors []FieldError
+
+// Error lists every problem, one per line
+func (ve ValidationErrors) Error() string {
+	if len(ve) == 1 {
+		return ve[0].Error()
+	}
+
+	lines := make([]string, 0, len(ve)+1)
+	lines = append(lines, fmt.Sprintf("%d problems:", len(ve)))
+	for _, e := range ve {
+		lines = append(lines, "  - "+e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// add records a problem at path
+func (ve *ValidationErrors) add(path, format string, args ...interface{}) {
+	*ve = append(*ve, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns nil when no problems were recorded
+func (ve ValidationErrors) err() error {
+	if len(ve) == 0 {
+		return nil
+	}
+	return ve
+}
+
+// checkURL records a problem unless raw is an absolute http(s) URL
+func (ve *ValidationErrors) checkURL(path, raw string) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		ve.add(path, "invalid URL: %v", err)
+		return
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		ve.add(path, "must be an absolute http or https URL, got %q", raw)
+	}
+}
+
+// sortedKeys returns the keys of a string-keyed map in order, so problems
+// are reported deterministically
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/payload"
+)
+
+// Scene is what an export draws: the boundaries, the templates where they
+// sit in the payload file, and every generated order
+type Scene struct {
+	Boundaries []config.PolygonBoundary
+	References []payload.Reference
+	Orders     []payload.OrderPayload
+	States     map[string]payload.OrderState // Final state by order number; nil before a run
+}
+
+// State returns the final state of an order, empty when it is not known
+func (s Scene) State(orderNumber string) payload.OrderState {
+	return s.States[orderNumber]
+}
+
+// Color returns the stroke color of an order: by final state once the run
+// is over, by order type before
+func (s Scene) Color(order payload.OrderPayload) string {
+	if state := s.State(order.OrderNumber); state != "" {
+		return payload.StateColor(state)
+	}
+	return payload.TypeColor(order.Type)
+}
+
+// Boundary and reference colors, as in the GeoJSON dump
+const (
+	boundaryColor  = "#ff0000"
+	referenceColor = "#0000ff"
+)
+
+// renderers writes a scene in each export format
+var renderers = map[string]func(io.Writer, Scene) error{
+	config.ExportKML:  writeKML,
+	config.ExportGPX:  writeGPX,
+	config.ExportCSV:  writeCSV,
+	config.ExportHTML: writeHTML,
+}
+
+// Write renders the scene in every format to dir as orders_<stamp>.<format>
+// and returns the files written
+func Write(scene Scene, formats []string, dir, stamp string) ([]string, error) {
+	if len(formats) == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	var files []string
+	for _, format := range formats {
+		render, ok := renderers[format]
+		if !ok {
+			return files, fmt.Errorf("unsupported export format %q", format)
+		}
+
+		name := filepath.Join(dir, fmt.Sprintf("orders_%s.%s", stamp, format))
+		if err := writeFile(name, scene, render); err != nil {
+			return files, fmt.Errorf("failed to write %s export: %w", format, err)
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+// writeFile renders the scene into a new file
+func writeFile(name string, scene Scene, render func(io.Writer, Scene) error) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	if err := render(w, scene); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeCSV writes one row per boundary, reference and order, with the
+// geometry as WKT
+func writeCSV(w io.Writer, scene Scene) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"feature", "name", "type", "state", "identity", "fault", "expectedOutcome", "wkt"})
+
+	for _, b := range scene.Boundaries {
+		out.Write([]string{"boundary", b.Name, config.GeometryMultiPolygon, "", "", "", "", multiPolygonWKT(b.AllPolygons())})
+	}
+	for _, ref := range scene.References {
+		out.Write([]string{"reference", ref.Name, ref.Geometry.Type, "", "", "", "", geometryWKT(ref.Geometry)})
+	}
+	for _, o := range scene.Orders {
+		if o.Geometry == nil {
+			continue
+		}
+		out.Write([]string{"order", o.OrderNumber, string(o.Type), string(scene.State(o.OrderNumber)),
+			o.Identity, o.Fault, o.ExpectedOutcome, geometryWKT(o.Geometry)})
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// geometryWKT renders a geometry as well-known text
+func geometryWKT(g *payload.GeoJSONGeometry) string {
+	switch g.Type {
+	case config.GeometryPoint:
+		return "POINT " + wktList(g.Coordinates)
+	case config.GeometryPolygon:
+		return "POLYGON " + wktParts(g.Parts)
+	case config.GeometryMultiLineString:
+		return "MULTILINESTRING " + wktParts(g.Parts)
+	default:
+		return "LINESTRING " + wktList(g.Coordinates)
+	}
+}
+
+// multiPolygonWKT renders polygons, each a list of rings, as a MULTIPOLYGON
+func multiPolygonWKT(polygons [][][][]float64) string {
+	rendered := make([]string, len(polygons))
+	for i, polygon := range polygons {
+		rendered[i] = wktParts(polygon)
+	}
+	return "MULTIPOLYGON (" + strings.Join(rendered, ", ") + ")"
+}
+
+// wktParts renders parenthesised lists of positions
+func wktParts(parts [][][]float64) string {
+	rendered := make([]string, len(parts))
+	for i, part := range parts {
+		rendered[i] = wktList(part)
+	}
+	return "(" + strings.Join(rendered, ", ") + ")"
+}
+
+// wktList renders positions as "(x y, x y)"
+func wktList(positions [][]float64) string {
+	rendered := make([]string, len(positions))
+	for i, p := range positions {
+		rendered[i] = formatFloat(p[0]) + " " + formatFloat(p[1])
+	}
+	return "(" + strings.Join(rendered, ", ") + ")"
+}
+
+// formatFloat renders a coordinate with as many digits as it needs
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+// writeGPX writes every order as a track, one segment per line or ring, and
+// Point orders as waypoints. Boundary rings become tracks too, as GPX has no
+// polygons. Positions are timed from coordTimes or M values when present.
+func writeGPX(w io.Writer, scene Scene) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<gpx version="1.1" creator="gameday-sim" xmlns="http://www.topografix.com/GPX/1/1">` + "\n")
+
+	for _, o := range scene.Orders {
+		if o.Geometry != nil && o.Geometry.Type == config.GeometryPoint && len(o.Geometry.Coordinates) > 0 {
+			p := o.Geometry.Coordinates[0]
+			fmt.Fprintf(&b, "<wpt lat=\"%s\" lon=\"%s\">%s<name>%s</name><type>%s</type></wpt>\n",
+				formatFloat(p[1]), formatFloat(p[0]), gpxTime(o.CoordTimes, 0, 0, p), escape(o.OrderNumber), escape(string(o.Type)))
+		}
+	}
+
+	for _, boundary := range scene.Boundaries {
+		var rings [][][]float64
+		for _, polygon := range boundary.AllPolygons() {
+			rings = append(rings, polygon...)
+		}
+		gpxTrack(&b, "Boundary "+boundary.Name, "boundary", "", rings, nil)
+	}
+	for _, ref := range scene.References {
+		gpxTrack(&b, ref.Name, "reference", "", ref.Geometry.Lines(), nil)
+	}
+	for _, o := range scene.Orders {
+		if o.Geometry == nil || o.Geometry.Type == config.GeometryPoint {
+			continue
+		}
+		desc := strings.TrimSpace(fmt.Sprintf("state=%s fault=%s", scene.State(o.OrderNumber), o.Fault))
+		gpxTrack(&b, o.OrderNumber, string(o.Type), desc, o.Geometry.Lines(), o.CoordTimes)
+	}
+
+	b.WriteString("</gpx>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// gpxTrack writes a named track with a segment per line
+func gpxTrack(b *strings.Builder, name, kind, desc string, lines [][][]float64, times [][]time.Time) {
+	b.WriteString("<trk>")
+	fmt.Fprintf(b, "<name>%s</name>", escape(name))
+	if desc != "" {
+		fmt.Fprintf(b, "<desc>%s</desc>", escape(desc))
+	}
+	fmt.Fprintf(b, "<type>%s</type>\n", escape(kind))
+	for l, line := range lines {
+		b.WriteString("<trkseg>\n")
+		for i, p := range line {
+			fmt.Fprintf(b, "<trkpt lat=\"%s\" lon=\"%s\">%s</trkpt>\n", formatFloat(p[1]), formatFloat(p[0]), gpxTime(times, l, i, p))
+		}
+		b.WriteString("</trkseg>\n")
+	}
+	b.WriteString("</trk>\n")
+}
+
+// gpxTime returns the time element of a position, from coordTimes or its M value
+func gpxTime(times [][]time.Time, line, index int, position []float64) string {
+	switch {
+	case line < len(times) && index < len(times[line]):
+		return "<time>" + times[line][index].UTC().Format(time.RFC3339Nano) + "</time>"
+	case len(position) >= 4:
+		t := time.UnixMilli(int64(math.Round(position[3] * 1000)))
+		return "<time>" + t.UTC().Format(time.RFC3339Nano) + "</time>"
+	default:
+		return ""
+	}
+}
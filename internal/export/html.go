@@ -0,0 +1,249 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/payload"
+)
+
+// mapWidth is the width of the SVG map in user units; the height follows
+// the aspect of the drawn area
+const mapWidth = 1000.0
+
+// svgProjection maps positions onto the SVG canvas with an equirectangular
+// projection around the middle latitude, north up
+type svgProjection struct {
+	minX, maxY float64
+	lngScale   float64 // Shrinks longitude by the cosine of the middle latitude
+	k          float64 // User units per projected degree
+	height     float64
+}
+
+// newSVGProjection fits the given positions, with a margin, into the map
+// width. Out of range positions are left out of the fit.
+func newSVGProjection(positions [][]float64) svgProjection {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range positions {
+		if math.Abs(p[0]) > 180 || math.Abs(p[1]) > 90 {
+			continue
+		}
+		minX, maxX = math.Min(minX, p[0]), math.Max(maxX, p[0])
+		minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+	}
+	if math.IsInf(minX, 1) {
+		minX, minY, maxX, maxY = -1, -1, 1, 1
+	}
+
+	lngScale := math.Cos((minY + maxY) / 2 * math.Pi / 180)
+	spanX := math.Max((maxX-minX)*lngScale, 1e-9)
+	spanY := math.Max(maxY-minY, 1e-9)
+	margin := math.Max(spanX, spanY) * 0.05
+
+	k := mapWidth / (spanX + 2*margin)
+	return svgProjection{
+		minX:     minX - margin/lngScale,
+		maxY:     maxY + margin,
+		lngScale: lngScale,
+		k:        k,
+		height:   (spanY + 2*margin) * k,
+	}
+}
+
+// xy returns the SVG coordinates of a position
+func (p svgProjection) xy(position []float64) (x, y float64) {
+	return (position[0] - p.minX) * p.lngScale * p.k, (p.maxY - position[1]) * p.k
+}
+
+// point returns the SVG coordinates of a position as "x,y"
+func (p svgProjection) point(position []float64) string {
+	x, y := p.xy(position)
+	return fmt.Sprintf("%.2f,%.2f", x, y)
+}
+
+// path returns SVG path data for lines, closing them when closed is set
+func (p svgProjection) path(lines [][][]float64, closed bool) string {
+	var d strings.Builder
+	for _, line := range lines {
+		for i, position := range line {
+			if i == 0 {
+				d.WriteString("M")
+			} else {
+				d.WriteString(" L")
+			}
+			d.WriteString(p.point(position))
+		}
+		if closed {
+			d.WriteString(" Z ")
+		} else {
+			d.WriteString(" ")
+		}
+	}
+	return strings.TrimSpace(d.String())
+}
+
+// writeHTML writes a self-contained page with an SVG map of the scene. It
+// needs no network: there are no tiles, and a few lines of script handle
+// zooming with the wheel and panning by dragging. Hovering an order shows
+// its details.
+func writeHTML(w io.Writer, scene Scene) error {
+	proj := newSVGProjection(fitPositions(scene))
+
+	var svg strings.Builder
+	for _, b := range scene.Boundaries {
+		for _, polygon := range b.AllPolygons() {
+			fmt.Fprintf(&svg, `<path class="boundary" d="%s"><title>%s</title></path>`+"\n",
+				proj.path(polygon, true), escape("Boundary "+b.Name))
+		}
+	}
+	for _, ref := range scene.References {
+		fmt.Fprintf(&svg, `<g class="reference"><title>%s</title>%s</g>`+"\n",
+			escape(ref.Name), svgGeometry(proj, ref.Geometry, referenceColor))
+	}
+
+	legend := make(map[string]string)
+	for _, o := range scene.Orders {
+		if o.Geometry == nil {
+			continue
+		}
+		color := scene.Color(o)
+		label := string(o.Type)
+		if state := scene.State(o.OrderNumber); state != "" {
+			label = string(state)
+		}
+		legend[label] = color
+
+		title := fmt.Sprintf("%s\ntype: %s", o.OrderNumber, o.Type)
+		if state := scene.State(o.OrderNumber); state != "" {
+			title += "\nstate: " + string(state)
+		}
+		if o.Identity != "" {
+			title += "\nidentity: " + o.Identity
+		}
+		if o.Fault != "" {
+			title += fmt.Sprintf("\nfault: %s (expected %s)", o.Fault, o.ExpectedOutcome)
+		}
+		class := "order"
+		if o.Type == payload.TypeAccepted {
+			class += " accepted"
+		}
+		fmt.Fprintf(&svg, `<g class="%s"><title>%s</title>%s</g>`+"\n", class, escape(title), svgGeometry(proj, o.Geometry, color))
+	}
+
+	labels := make([]string, 0, len(legend))
+	for label := range legend {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	var items strings.Builder
+	for _, label := range labels {
+		fmt.Fprintf(&items, `<li><span style="background:%s"></span>%s</li>`, legend[label], escape(label))
+	}
+
+	_, err := fmt.Fprintf(w, htmlPage, len(scene.Orders), items.String(), mapWidth, proj.height, svg.String())
+	return err
+}
+
+// svgGeometry draws a geometry: points as circles, polygons filled
+func svgGeometry(proj svgProjection, g *payload.GeoJSONGeometry, color string) string {
+	switch g.Type {
+	case config.GeometryPoint:
+		if len(g.Coordinates) == 0 {
+			return ""
+		}
+		x, y := proj.xy(g.Coordinates[0])
+		return fmt.Sprintf(`<circle cx="%.2f" cy="%.2f" r="3" fill="%s"/>`, x, y, color)
+	case config.GeometryPolygon:
+		return fmt.Sprintf(`<path d="%s" stroke="%s" fill="%s" fill-opacity="0.2"/>`, proj.path(g.Parts, true), color, color)
+	default:
+		return fmt.Sprintf(`<path d="%s" stroke="%s" fill="none"/>`, proj.path(g.Lines(), false), color)
+	}
+}
+
+// fitPositions returns the positions the map is fitted to: the boundaries
+// when there are any, so stray faulty orders do not shrink the view, or
+// else every reference and order
+func fitPositions(scene Scene) [][]float64 {
+	var positions [][]float64
+	for _, b := range scene.Boundaries {
+		for _, polygon := range b.AllPolygons() {
+			for _, ring := range polygon {
+				positions = append(positions, ring...)
+			}
+		}
+	}
+	if len(positions) > 0 {
+		return positions
+	}
+
+	for _, ref := range scene.References {
+		for _, line := range ref.Geometry.Lines() {
+			positions = append(positions, line...)
+		}
+	}
+	for _, o := range scene.Orders {
+		if o.Geometry != nil {
+			for _, line := range o.Geometry.Lines() {
+				positions = append(positions, line...)
+			}
+		}
+	}
+	return positions
+}
+
+// htmlPage is the page template: order count, legend items, SVG width and
+// height, and the SVG content
+const htmlPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>gameday-sim orders</title>
+<style>
+body { margin: 0; font-family: sans-serif; background: #fafafa; }
+header { padding: 8px 12px; border-bottom: 1px solid #ddd; }
+header ul { display: inline; margin: 0; padding: 0; }
+header li { display: inline-block; margin-left: 16px; list-style: none; }
+header li span { display: inline-block; width: 12px; height: 12px; margin-right: 4px; vertical-align: middle; }
+svg { width: 100vw; height: calc(100vh - 40px); cursor: grab; }
+path { vector-effect: non-scaling-stroke; stroke-width: 2; }
+.boundary { fill: #ff0000; fill-opacity: 0.1; stroke: #ff0000; fill-rule: evenodd; }
+.reference path { stroke-width: 3; }
+.accepted path { stroke-dasharray: 6 3; }
+g.order:hover path { stroke-width: 4; }
+</style>
+</head>
+<body>
+<header><strong>%d orders</strong> <ul>%s</ul> <small>dashed: accepted-only orders; scroll to zoom, drag to pan</small></header>
+<svg id="map" viewBox="0 0 %.2f %.2f" xmlns="http://www.w3.org/2000/svg">
+%s</svg>
+<script>
+(function () {
+  var svg = document.getElementById("map");
+  var box = svg.viewBox.baseVal;
+  var drag = null;
+  function scale() { return box.width / svg.clientWidth; }
+  svg.addEventListener("wheel", function (e) {
+    e.preventDefault();
+    var f = e.deltaY > 0 ? 1.2 : 1 / 1.2;
+    var r = svg.getBoundingClientRect();
+    var x = box.x + (e.clientX - r.left) * scale(), y = box.y + (e.clientY - r.top) * scale();
+    box.x = x - (x - box.x) * f; box.y = y - (y - box.y) * f;
+    box.width *= f; box.height *= f;
+  });
+  svg.addEventListener("mousedown", function (e) { drag = [e.clientX, e.clientY]; });
+  window.addEventListener("mouseup", function () { drag = null; });
+  window.addEventListener("mousemove", function (e) {
+    if (!drag) return;
+    box.x -= (e.clientX - drag[0]) * scale(); box.y -= (e.clientY - drag[1]) * scale();
+    drag = [e.clientX, e.clientY];
+  });
+})();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,123 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/payload"
+)
+
+// writeKML writes a KML document with a styled placemark per boundary,
+// reference and order. Order details go into ExtendedData.
+func writeKML(w io.Writer, scene Scene) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<kml xmlns="http://www.opengis.net/kml/2.2">` + "\n<Document>\n<name>gameday-sim orders</name>\n")
+
+	for _, boundary := range scene.Boundaries {
+		b.WriteString("<Placemark>\n")
+		fmt.Fprintf(&b, "<name>%s</name>\n", escape("Boundary "+boundary.Name))
+		fmt.Fprintf(&b, "<Style><LineStyle><color>%s</color><width>2</width></LineStyle><PolyStyle><color>%s</color></PolyStyle></Style>\n",
+			kmlColor(boundaryColor, 0xff), kmlColor(boundaryColor, 0x1a))
+		b.WriteString("<MultiGeometry>\n")
+		for _, polygon := range boundary.AllPolygons() {
+			kmlPolygon(&b, polygon)
+		}
+		b.WriteString("</MultiGeometry>\n</Placemark>\n")
+	}
+
+	for _, ref := range scene.References {
+		b.WriteString("<Placemark>\n")
+		fmt.Fprintf(&b, "<name>%s</name>\n", escape(ref.Name))
+		fmt.Fprintf(&b, "<Style><LineStyle><color>%s</color><width>3</width></LineStyle></Style>\n", kmlColor(referenceColor, 0xff))
+		kmlGeometry(&b, ref.Geometry)
+		b.WriteString("</Placemark>\n")
+	}
+
+	for _, o := range scene.Orders {
+		if o.Geometry == nil {
+			continue
+		}
+		b.WriteString("<Placemark>\n")
+		fmt.Fprintf(&b, "<name>%s</name>\n", escape(o.OrderNumber))
+		fmt.Fprintf(&b, "<Style><LineStyle><color>%s</color><width>2</width></LineStyle></Style>\n", kmlColor(scene.Color(o), 0xff))
+		b.WriteString("<ExtendedData>\n")
+		for _, field := range [][2]string{
+			{"type", string(o.Type)},
+			{"state", string(scene.State(o.OrderNumber))},
+			{"identity", o.Identity},
+			{"fault", o.Fault},
+			{"expectedOutcome", o.ExpectedOutcome},
+		} {
+			if field[1] != "" {
+				fmt.Fprintf(&b, "<Data name=\"%s\"><value>%s</value></Data>\n", field[0], escape(field[1]))
+			}
+		}
+		b.WriteString("</ExtendedData>\n")
+		kmlGeometry(&b, o.Geometry)
+		b.WriteString("</Placemark>\n")
+	}
+
+	b.WriteString("</Document>\n</kml>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// kmlGeometry writes a geometry as the matching KML element
+func kmlGeometry(b *strings.Builder, g *payload.GeoJSONGeometry) {
+	switch g.Type {
+	case config.GeometryPoint:
+		fmt.Fprintf(b, "<Point><coordinates>%s</coordinates></Point>\n", kmlCoordinates(g.Coordinates))
+	case config.GeometryPolygon:
+		kmlPolygon(b, g.Parts)
+	case config.GeometryMultiLineString:
+		b.WriteString("<MultiGeometry>\n")
+		for _, line := range g.Parts {
+			fmt.Fprintf(b, "<LineString><coordinates>%s</coordinates></LineString>\n", kmlCoordinates(line))
+		}
+		b.WriteString("</MultiGeometry>\n")
+	default:
+		fmt.Fprintf(b, "<LineString><coordinates>%s</coordinates></LineString>\n", kmlCoordinates(g.Coordinates))
+	}
+}
+
+// kmlPolygon writes an exterior ring followed by its holes
+func kmlPolygon(b *strings.Builder, rings [][][]float64) {
+	if len(rings) == 0 {
+		return
+	}
+	b.WriteString("<Polygon>\n")
+	fmt.Fprintf(b, "<outerBoundaryIs><LinearRing><coordinates>%s</coordinates></LinearRing></outerBoundaryIs>\n", kmlCoordinates(rings[0]))
+	for _, hole := range rings[1:] {
+		fmt.Fprintf(b, "<innerBoundaryIs><LinearRing><coordinates>%s</coordinates></LinearRing></innerBoundaryIs>\n", kmlCoordinates(hole))
+	}
+	b.WriteString("</Polygon>\n")
+}
+
+// kmlCoordinates renders positions as KML's space separated "lng,lat" tuples
+func kmlCoordinates(positions [][]float64) string {
+	rendered := make([]string, len(positions))
+	for i, p := range positions {
+		rendered[i] = formatFloat(p[0]) + "," + formatFloat(p[1])
+	}
+	return strings.Join(rendered, " ")
+}
+
+// kmlColor converts "#rrggbb" to KML's aabbggrr with the given opacity
+func kmlColor(hex string, alpha uint8) string {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return fmt.Sprintf("%02xffffff", alpha)
+	}
+	return fmt.Sprintf("%02x%s%s%s", alpha, hex[4:6], hex[2:4], hex[0:2])
+}
+
+// escape makes text safe for XML and HTML content and attributes
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package payload
+
+import (
+	"math"
+
+	"gameday-sim/internal/config"
+)
+
+// area is a single polygon of a named boundary: an exterior ring followed by
+// holes that orders must stay out of
+type area struct {
+	boundary string
+	rings    [][][]float64
+}
+
+// boundaryAreas flattens every polygon of every boundary into areas
+func boundaryAreas(payloadData *config.PayloadData) []area {
+	var areas []area
+	for _, b := range payloadData.BoundarySet() {
+		for _, polygon := range b.AllPolygons() {
+			if len(polygon) > 0 {
+				areas = append(areas, area{boundary: b.Name, rings: polygon})
+			}
+		}
+	}
+	return areas
+}
+
+// containsPoint reports whether a point lies inside the exterior ring and
+// outside every hole
+func (a area) containsPoint(lng, lat float64) bool {
+	if !pointInRing(lng, lat, a.rings[0]) {
+		return false
+	}
+	for _, hole := range a.rings[1:] {
+		if pointInRing(lng, lat, hole) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsGeometry reports whether every part of a geometry lies inside the
+// area: each vertex is inside, no segment crosses an exterior or hole edge,
+// and a polygon geometry does not enclose a hole
+func (a area) containsGeometry(geometryType string, parts [][][]float64) bool {
+	for _, part := range parts {
+		for _, point := range part {
+			if !a.containsPoint(point[0], point[1]) {
+				return false
+			}
+		}
+		for i := 1; i < len(part); i++ {
+			if a.crossesEdge(part[i-1], part[i]) {
+				return false
+			}
+		}
+	}
+
+	if geometryType == config.GeometryPolygon && len(parts) > 0 {
+		for _, hole := range a.rings[1:] {
+			if len(hole) > 0 && pointInRing(hole[0][0], hole[0][1], parts[0]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// crossesEdge reports whether the segment p-q touches any ring edge of the area
+func (a area) crossesEdge(p, q []float64) bool {
+	for _, ring := range a.rings {
+		for i := 1; i < len(ring); i++ {
+			if segmentsIntersect(p, q, ring[i-1], ring[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// minLatitude returns the southernmost latitude of the exterior ring
+func (a area) minLatitude() float64 {
+	minLat := math.Inf(1)
+	for _, point := range a.rings[0] {
+		minLat = math.Min(minLat, point[1])
+	}
+	return minLat
+}
+
+// pointInRing uses the ray casting algorithm to check if a point is inside a ring
+func pointInRing(lng, lat float64, ring [][]float64) bool {
+	inside := false
+	j := len(ring) - 1
+
+	for i := 0; i < len(ring); i++ {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+
+		intersect := ((yi > lat) != (yj > lat)) &&
+			(lng < (xj-xi)*(lat-yi)/(yj-yi)+xi)
+
+		if intersect {
+			inside = !inside
+		}
+
+		j = i
+	}
+
+	return inside
+}
+
+// segmentsIntersect reports whether segments p1-p2 and q1-q2 share any
+// point, including touching endpoints and collinear overlap
+func segmentsIntersect(p1, p2, q1, q2 []float64) bool {
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	d3 := orientation(p1, p2, q1)
+	d4 := orientation(p1, p2, q2)
+
+	if d1 != d2 && d3 != d4 && d1 != 0 && d2 != 0 && d3 != 0 && d4 != 0 {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(q1, q2, p1)) ||
+		(d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) ||
+		(d4 == 0 && onSegment(p1, p2, q2))
+}
+
+// orientation returns the sign of the cross product (b-a) x (c-a): 1 for a
+// counter-clockwise turn, -1 for clockwise and 0 when collinear
+func orientation(a, b, c []float64) int {
+	cross := (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+	switch {
+	case cross > 0:
+		return 1
+	case cross < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// onSegment reports whether c, known to be collinear with a-b, lies within its bounding box
+func onSegment(a, b, c []float64) bool {
+	return c[0] >= math.Min(a[0], b[0]) && c[0] <= math.Max(a[0], b[0]) &&
+		c[1] >= math.Min(a[1], b[1]) && c[1] <= math.Max(a[1], b[1])
+}
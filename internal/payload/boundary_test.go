@@ -0,0 +1,76 @@
+package payload
+
+import (
+	"testing"
+
+	"gameday-sim/internal/config"
+)
+
+// square returns a closed ring for the axis-aligned square [x0,x1] x [y0,y1]
+func square(x0, y0, x1, y1 float64) [][]float64 {
+	return [][]float64{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}, {x0, y0}}
+}
+
+func TestIsGeometryInBoundary_HolesAndSegments(t *testing.T) {
+	// A U-shaped (concave) exterior with a hole in the left arm
+	uShape := [][]float64{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 0}}
+	hole := square(0.2, 1.5, 0.8, 2.5)
+
+	payloadData := &config.PayloadData{
+		Boundary: config.PolygonBoundary{Coordinates: [][][]float64{uShape, hole}},
+		Boundaries: []config.PolygonBoundary{{
+			Name:     "island",
+			Polygons: [][][][]float64{{square(10, 10, 11, 11)}, {square(20, 20, 21, 21)}},
+		}},
+	}
+	gen := (&Generator{payloadData: payloadData}).layout()
+
+	testCases := []struct {
+		name         string
+		geometryType string
+		parts        [][][]float64
+		expected     bool
+	}{
+		{"line in the base", config.GeometryLineString, [][][]float64{{{0.5, 0.5}, {2.5, 0.5}}}, true},
+		{"segment across the notch", config.GeometryLineString, [][][]float64{{{0.5, 2.8}, {2.5, 2.8}}}, false},
+		{"vertex in the hole", config.GeometryPoint, [][][]float64{{{0.5, 2}}}, false},
+		{"segment through the hole", config.GeometryLineString, [][][]float64{{{0.5, 1.2}, {0.5, 2.8}}}, false},
+		{"polygon enclosing the hole", config.GeometryPolygon, [][][]float64{square(0.1, 1.2, 0.9, 2.8)}, false},
+		{"line beside the hole", config.GeometryLineString, [][][]float64{{{0.9, 1.2}, {0.9, 2.8}}}, true},
+		{"second polygon of a multipolygon", config.GeometryLineString, [][][]float64{{{20.5, 20.2}, {20.5, 20.8}}}, true},
+		{"parts in different polygons", config.GeometryMultiLineString,
+			[][][]float64{{{10.5, 10.2}, {10.5, 10.8}}, {{20.5, 20.2}, {20.5, 20.8}}}, false},
+		{"outside every boundary", config.GeometryPoint, [][][]float64{{{5, 5}}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := gen.isGeometryInBoundary(tc.geometryType, tc.parts); got != tc.expected {
+				t.Errorf("isGeometryInBoundary() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSegmentsIntersect(t *testing.T) {
+	testCases := []struct {
+		name           string
+		p1, p2, q1, q2 []float64
+		expected       bool
+	}{
+		{"crossing", []float64{0, 0}, []float64{2, 2}, []float64{0, 2}, []float64{2, 0}, true},
+		{"parallel", []float64{0, 0}, []float64{2, 0}, []float64{0, 1}, []float64{2, 1}, false},
+		{"touching endpoint", []float64{0, 0}, []float64{1, 1}, []float64{1, 1}, []float64{2, 0}, true},
+		{"collinear overlap", []float64{0, 0}, []float64{2, 0}, []float64{1, 0}, []float64{3, 0}, true},
+		{"collinear apart", []float64{0, 0}, []float64{1, 0}, []float64{2, 0}, []float64{3, 0}, false},
+		{"short of crossing", []float64{0, 0}, []float64{1, 1}, []float64{0, 3}, []float64{3, 0}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := segmentsIntersect(tc.p1, tc.p2, tc.q1, tc.q2); got != tc.expected {
+				t.Errorf("segmentsIntersect() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
@@ -2,36 +2,82 @@ package payload
 
 import (
 	"fmt"
+	"math/rand"
+	"time"
+)
+
+// DistributionStrategy controls how payloads are assigned to batches
+type DistributionStrategy string
+
+const (
+	DistributeSequential  DistributionStrategy = "sequential"  // Slice payloads in generation order
+	DistributeShuffled    DistributionStrategy = "shuffled"    // Shuffle, then slice
+	DistributeStratified  DistributionStrategy = "stratified"  // Every batch gets the same activate/accepted ratio
+	DistributeHomogeneous DistributionStrategy = "homogeneous" // Every batch holds a single order type
 )
 
 // Batch represents a collection of payloads to be processed together
 type Batch struct {
-	ID       int
-	Payloads []OrderPayload
+	ID       int            `json:"id"`
+	Type     OrderType      `json:"type,omitempty"` // Set when every payload in the batch has the same type
+	Payloads []OrderPayload `json:"payloads"`
 }
 
 // Distributor handles distribution of payloads into batches
 type Distributor struct {
 	batchSize int
+	strategy  DistributionStrategy
+	rng       *rand.Rand
 }
 
-// NewDistributor creates a new payload distributor
+// NewDistributor creates a new payload distributor that slices payloads sequentially
 func NewDistributor(batchSize int) *Distributor {
+	return NewDistributorWithStrategy(batchSize, DistributeSequential, 0)
+}
+
+// NewDistributorWithStrategy creates a distributor using the given strategy.
+// A zero seed picks a time-based seed for the shuffled strategy.
+func NewDistributorWithStrategy(batchSize int, strategy DistributionStrategy, seed int64) *Distributor {
+	if strategy == "" {
+		strategy = DistributeSequential
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
 	return &Distributor{
 		batchSize: batchSize,
+		strategy:  strategy,
+		rng:       rand.New(rand.NewSource(seed)),
 	}
 }
 
-// Distribute divides payloads into batches
+// Distribute divides payloads into batches according to the strategy
 func (d *Distributor) Distribute(payloads []OrderPayload) []Batch {
 	if len(payloads) == 0 {
 		return nil
 	}
 
-	// Calculate number of batches needed
-	numBatches := (len(payloads) + d.batchSize - 1) / d.batchSize
-	batches := make([]Batch, 0, numBatches)
+	switch d.strategy {
+	case DistributeShuffled:
+		shuffled := append([]OrderPayload(nil), payloads...)
+		d.rng.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+		return d.slice(shuffled, nil)
+	case DistributeStratified:
+		return d.slice(interleaveByType(payloads), nil)
+	case DistributeHomogeneous:
+		activate, accepted := splitByType(payloads)
+		return d.slice(accepted, d.slice(activate, nil))
+	default:
+		return d.slice(payloads, nil)
+	}
+}
 
+// slice cuts payloads into batchSize chunks and appends them to batches,
+// continuing the batch ID sequence
+func (d *Distributor) slice(payloads []OrderPayload, batches []Batch) []Batch {
 	for i := 0; i < len(payloads); i += d.batchSize {
 		end := i + d.batchSize
 		if end > len(payloads) {
@@ -42,12 +88,62 @@ func (d *Distributor) Distribute(payloads []OrderPayload) []Batch {
 			ID:       len(batches) + 1,
 			Payloads: payloads[i:end],
 		}
+		batch.Type = batchType(batch.Payloads)
 		batches = append(batches, batch)
 	}
 
 	return batches
 }
 
+// splitByType separates payloads into activate and accepted lists, preserving order
+func splitByType(payloads []OrderPayload) ([]OrderPayload, []OrderPayload) {
+	var activate, accepted []OrderPayload
+	for _, p := range payloads {
+		if p.Type == TypeActivate {
+			activate = append(activate, p)
+		} else {
+			accepted = append(accepted, p)
+		}
+	}
+	return activate, accepted
+}
+
+// interleaveByType spreads the two order types evenly through the list so
+// that any contiguous slice has (close to) the overall activate/accepted ratio
+func interleaveByType(payloads []OrderPayload) []OrderPayload {
+	activate, accepted := splitByType(payloads)
+	total := len(payloads)
+	result := make([]OrderPayload, 0, total)
+
+	ai, ci := 0, 0
+	for i := 0; i < total; i++ {
+		// Place an activate order while that keeps their running count at the
+		// rounded target share of (i+1) * len(activate) / total
+		if ai < len(activate) && (ci >= len(accepted) || 2*(ai+1)*total <= 2*(i+1)*len(activate)+total) {
+			result = append(result, activate[ai])
+			ai++
+		} else {
+			result = append(result, accepted[ci])
+			ci++
+		}
+	}
+
+	return result
+}
+
+// batchType returns the shared order type of the payloads, or "" for mixed batches
+func batchType(payloads []OrderPayload) OrderType {
+	if len(payloads) == 0 {
+		return ""
+	}
+	for _, p := range payloads[1:] {
+		if p.Type != payloads[0].Type {
+			return ""
+		}
+	}
+	return payloads[0].Type
+}
+
 // GetBatchStats returns statistics about batch distribution
 func (d *Distributor) GetBatchStats(batches []Batch) map[string]interface{} {
 	if len(batches) == 0 {
@@ -62,24 +158,46 @@ func (d *Distributor) GetBatchStats(batches []Batch) map[string]interface{} {
 	totalPayloads := 0
 	activateCount := 0
 	acceptedCount := 0
+	homogeneousBatches := 0
+	minRatio, maxRatio := 1.0, 0.0
 
 	for _, batch := range batches {
 		totalPayloads += len(batch.Payloads)
+		batchActivate := 0
 		for _, payload := range batch.Payloads {
 			if payload.Type == TypeActivate {
 				activateCount++
+				batchActivate++
 			} else {
 				acceptedCount++
 			}
 		}
+
+		if batch.Type != "" {
+			homogeneousBatches++
+		}
+		if len(batch.Payloads) > 0 {
+			ratio := float64(batchActivate) / float64(len(batch.Payloads))
+			if ratio < minRatio {
+				minRatio = ratio
+			}
+			if ratio > maxRatio {
+				maxRatio = ratio
+			}
+		}
 	}
 
 	return map[string]interface{}{
-		"totalBatches":   len(batches),
-		"totalPayloads":  totalPayloads,
-		"activateOrders": activateCount,
-		"acceptedOrders": acceptedCount,
-		"avgBatchSize":   float64(totalPayloads) / float64(len(batches)),
+		"strategy":           string(d.strategy),
+		"totalBatches":       len(batches),
+		"totalPayloads":      totalPayloads,
+		"activateOrders":     activateCount,
+		"acceptedOrders":     acceptedCount,
+		"avgBatchSize":       float64(totalPayloads) / float64(len(batches)),
+		"homogeneousBatches": homogeneousBatches,
+		"mixedBatches":       len(batches) - homogeneousBatches,
+		"minActivateRatio":   minRatio,
+		"maxActivateRatio":   maxRatio,
 	}
 }
 
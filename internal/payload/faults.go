@@ -0,0 +1,136 @@
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+// ErrFaultUnsupported is returned by Generate when a configured fault kind
+// cannot be built from the payload data
+var ErrFaultUnsupported = errors.New("fault kind not supported by the payload data")
+
+// injectFaults replaces the geometry of payload.faults.fraction of the
+// orders, chosen at random, with deliberately bad geometry. Kinds are spread
+// over the chosen orders by weight, and each order is tagged with its kind
+// and the outcome expected from the service.
+func (g *Generator) injectFaults(payloads []OrderPayload) error {
+	fc := g.config.Payload.Faults
+	count := int(math.Round(fc.Fraction * float64(len(payloads))))
+	if count == 0 {
+		return nil
+	}
+
+	var kinds []string
+	var weights []int
+	for i, w := range fc.Weights() {
+		if w > 0 {
+			kinds = append(kinds, config.FaultKinds[i])
+			weights = append(weights, w)
+		}
+	}
+	for _, kind := range kinds {
+		if kind == config.FaultBoundary && len(g.areas) == 0 {
+			return fmt.Errorf("%w: %s needs a boundary", ErrFaultUnsupported, kind)
+		}
+		if kind == config.FaultOverlap && count >= len(payloads) {
+			return fmt.Errorf("%w: %s needs at least one order left intact to overlap", ErrFaultUnsupported, kind)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(faultSeed(g.config)))
+	chosen := rng.Perm(len(payloads))[:count]
+	faulty := make(map[int]bool, count)
+	for _, i := range chosen {
+		faulty[i] = true
+	}
+
+	picker := newSmoothWeighted(weights)
+	injected := 0
+	for _, i := range chosen {
+		kind := kinds[picker.next()]
+		p := &payloads[i]
+		if p.Geometry == nil {
+			continue
+		}
+		geometry, err := g.faultGeometry(kind, p.Geometry, payloads, faulty, rng)
+		if err != nil {
+			return err
+		}
+		p.Geometry = geometry
+		p.Fault = kind
+		p.ExpectedOutcome = fc.ExpectedOutcome(kind)
+		injected++
+	}
+
+	log.Printf("Injected faults into %d of %d orders", injected, len(payloads))
+	return nil
+}
+
+// faultSeed returns the seed for choosing faulty orders, falling back to a
+// time-based seed when the run is not seeded
+func faultSeed(cfg *config.Config) int64 {
+	if seed := cfg.Simulation.DeriveSeed("faults"); seed != 0 {
+		return seed
+	}
+	return time.Now().UnixNano()
+}
+
+// faultGeometry builds the bad geometry of a kind from an order's own
+// geometry. Overlaps copy a random intact order, and fail with
+// ErrFaultUnsupported when no intact order has geometry.
+func (g *Generator) faultGeometry(kind string, own *GeoJSONGeometry, payloads []OrderPayload, faulty map[int]bool, rng *rand.Rand) (*GeoJSONGeometry, error) {
+	origin := own.Lines()[0][0]
+	size := math.Max(g.delta.Longitude, g.delta.Latitude)
+	if minX, minY, maxX, maxY := bounds(flatten(own.Lines())); maxX-minX > 0 || maxY-minY > 0 {
+		size = math.Max(maxX-minX, maxY-minY)
+	}
+
+	switch kind {
+	case config.FaultOverlap:
+		var intact []int
+		for i, p := range payloads {
+			if !faulty[i] && p.Geometry != nil {
+				intact = append(intact, i)
+			}
+		}
+		if len(intact) == 0 {
+			return nil, fmt.Errorf("%w: %s needs an intact order with geometry to overlap", ErrFaultUnsupported, kind)
+		}
+		copied := payloads[intact[rng.Intn(len(intact))]].Geometry
+		return newGeometry(copied.Type, translate(copied.Lines(), 0, 0)), nil
+
+	case config.FaultBoundary:
+		// From the order's start to just east of every boundary
+		area := g.boundaryBox()
+		outside := []float64{area[2] + (area[2]-area[0])*0.1 + size, origin[1]}
+		return newGeometry(config.GeometryLineString, [][][]float64{{origin, outside}}), nil
+
+	case config.FaultSelfIntersect:
+		x, y := origin[0], origin[1]
+		bowTie := [][]float64{{x, y}, {x + size, y - size}, {x + size, y}, {x, y - size}}
+		if own.Type == config.GeometryPolygon {
+			return newGeometry(config.GeometryPolygon, [][][]float64{append(bowTie, []float64{x, y})}), nil
+		}
+		return newGeometry(config.GeometryLineString, [][][]float64{bowTie}), nil
+
+	case config.FaultDegenerate:
+		return newGeometry(config.GeometryLineString, [][][]float64{{origin}}), nil
+
+	case config.FaultAntimeridian:
+		return newGeometry(config.GeometryLineString, [][][]float64{{
+			{180 - size/2, origin[1]},
+			{-180 + size/2, origin[1] - size},
+		}}), nil
+
+	default: // config.FaultInvalidCoordinates
+		parts := translate(own.Lines(), 0, 0)
+		parts[0][0] = []float64{origin[0] + 360, 95}
+		return newGeometry(own.Type, parts), nil
+	}
+}
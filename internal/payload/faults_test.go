@@ -0,0 +1,32 @@
+package payload
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"gameday-sim/internal/config"
+)
+
+func TestFaultGeometry_OverlapWithoutIntactOrders(t *testing.T) {
+	cfg, payloadData := createTestConfigWithGeo()
+	gen := NewGenerator(cfg, payloadData)
+
+	line := &GeoJSONGeometry{Type: config.GeometryLineString, Coordinates: [][]float64{{0, 0}, {1, 1}}}
+	payloads := []OrderPayload{{Geometry: line}, {}, {}}
+	faulty := map[int]bool{0: true}
+
+	_, err := gen.faultGeometry(config.FaultOverlap, line, payloads, faulty, rand.New(rand.NewSource(1)))
+	if !errors.Is(err, ErrFaultUnsupported) {
+		t.Fatalf("expected ErrFaultUnsupported, got %v", err)
+	}
+
+	payloads[2].Geometry = &GeoJSONGeometry{Type: config.GeometryLineString, Coordinates: [][]float64{{2, 2}, {3, 3}}}
+	copied, err := gen.faultGeometry(config.FaultOverlap, line, payloads, faulty, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("faultGeometry: %v", err)
+	}
+	if copied.Lines()[0][0][0] != 2 {
+		t.Errorf("expected the only intact order to be copied, got %v", copied.Lines())
+	}
+}
@@ -2,9 +2,10 @@ package payload
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"math/rand"
+	"math"
 	"os"
 	"path/filepath"
 	"time"
@@ -16,32 +17,69 @@ import (
 type Generator struct {
 	config         *config.Config
 	payloadData    *config.PayloadData
-	rng            *rand.Rand
+	seed           int64
+	strategy       placementStrategy      // Chooses each position; the generator itself for zigzag and hex
+	hex            bool                   // Shift odd rows by half a column and pack rows closer
+	scale          scale                  // Converts configured spacing to degrees
+	delta          config.CoordinateDelta // Spacing in degrees
 	currentRow     int
 	currentCol     int
 	direction      int     // 1 for right, -1 for left
 	maxColInRow    int     // Track max column reached in current row
-	polylineHeight float64 // Vertical extent of the base polyline
+	polylineHeight float64 // Vertical extent of the tallest template
+	templates      []config.Template
+	picker         *smoothWeighted // Chooses the template for each order
+	areas          []area          // Polygons of every boundary
+	overflow       string          // Overflow policy in effect once the first layer is full
+	layer          int             // Current layout layer, 0 until the boundary first overflows
+	layerPlaced    int             // Placements made in the current layer
 }
 
 // NewGenerator creates a new payload generator
 func NewGenerator(cfg *config.Config, payloadData *config.PayloadData) *Generator {
-	// Calculate polyline vertical extent
-	polylineHeight := calculatePolylineHeight(payloadData.BasePolyline.Coordinates)
-
-	log.Printf("Generator initialized: polylineHeight=%.17f, delta.Lat=%.17f, rowSpacing=%.17f",
-		polylineHeight, payloadData.Delta.Latitude, polylineHeight+payloadData.Delta.Latitude)
-
-	return &Generator{
-		config:         cfg,
-		payloadData:    payloadData,
-		rng:            rand.New(rand.NewSource(time.Now().UnixNano())),
-		currentRow:     0,
-		currentCol:     0,
-		direction:      1, // Start moving right
-		maxColInRow:    -1,
-		polylineHeight: polylineHeight,
+	g := (&Generator{config: cfg, payloadData: payloadData, seed: generatorSeed(cfg)}).layout()
+
+	log.Printf("Generator initialized: polylineHeight=%.17f, delta.Lat=%.17f, rowSpacing=%.17f, templates=%d, placement=%s",
+		g.polylineHeight, g.delta.Latitude, g.rowSpacing(), len(g.templates), g.placementName())
+
+	return g
+}
+
+// generatorSeed returns the seed for payload generation, falling back to a
+// time-based seed when the run is not seeded
+func generatorSeed(cfg *config.Config) int64 {
+	if seed := cfg.Simulation.DeriveSeed("payload"); seed != 0 {
+		return seed
+	}
+	return time.Now().UnixNano()
+}
+
+// timestamp returns the payload timestamp, pinned to the configured epoch
+// for reproducible runs
+func (g *Generator) timestamp() time.Time {
+	if !g.config.Simulation.Epoch.IsZero() {
+		return g.config.Simulation.Epoch
 	}
+	return time.Now()
+}
+
+// templatesHeight returns the vertical extent of the tallest template, so
+// rows never overlap whichever templates land in them
+func templatesHeight(templates []config.Template) float64 {
+	height := 0.0
+	for _, t := range templates {
+		height = math.Max(height, calculatePolylineHeight(flatten(t.Parts)))
+	}
+	return height
+}
+
+// flatten returns every position of every part
+func flatten(parts [][][]float64) [][]float64 {
+	var positions [][]float64
+	for _, part := range parts {
+		positions = append(positions, part...)
+	}
+	return positions
 }
 
 // calculatePolylineHeight returns the vertical extent (max lat - min lat)
@@ -66,31 +104,168 @@ func calculatePolylineHeight(coords [][]float64) float64 {
 	return maxLat - minLat
 }
 
-// GenerateAll pre-generates all payloads for the simulation
-func (g *Generator) GenerateAll() []OrderPayload {
+// ErrBoundaryExhausted is returned when the boundary cannot hold totalOrders polylines
+var ErrBoundaryExhausted = errors.New("boundary exhausted")
+
+// Generate pre-generates all payloads for the simulation. It first checks
+// that the boundary holds every order and applies payload.overflow when it
+// does not, so generation never runs out of room part way through.
+func (g *Generator) Generate() ([]OrderPayload, error) {
 	totalOrders := g.config.Simulation.TotalOrders
 	activatedCount := g.config.Simulation.ActivatedCount
 
+	if err := g.planCapacity(totalOrders); err != nil {
+		return nil, err
+	}
+
 	payloads := make([]OrderPayload, 0, totalOrders)
 
 	// Generate payloads for "activate" type orders
-	for i := 0; i < activatedCount; i++ {
-		payloads = append(payloads, g.generatePayload(i, TypeActivate))
+	for i := 0; i < totalOrders; i++ {
+		orderType := TypeActivate
+		if i >= activatedCount {
+			// Generate payloads for "accepted" type orders
+			orderType = TypeAccepted
+		}
+		p, err := g.generatePayload(i, orderType)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, p)
+	}
+
+	// Mixing of types across batches is handled by the Distributor's strategy
+
+	if err := g.verify(payloads); err != nil {
+		return nil, err
+	}
+
+	// Faults go in after verification, which only covers the intended layout
+	if err := g.injectFaults(payloads); err != nil {
+		return nil, err
+	}
+
+	AssignIdentities(payloads, g.config.Identities)
+	g.applyTiming(payloads)
+
+	return payloads, nil
+}
+
+// maxLoggedOverlaps bounds how many overlapping pairs are logged individually
+const maxLoggedOverlaps = 10
+
+// verify checks the generated geometries for overlaps according to
+// payload.verify, failing only in strict mode
+func (g *Generator) verify(payloads []OrderPayload) error {
+	mode := g.config.Payload.Verify
+	if mode == config.VerifyOff {
+		return nil
+	}
+
+	overlaps := VerifyNonOverlap(payloads)
+	if len(overlaps) == 0 {
+		log.Printf("Verified %d geometries: no overlaps", len(payloads))
+		return nil
+	}
+
+	for i, o := range overlaps {
+		if i == maxLoggedOverlaps {
+			log.Printf("... and %d more overlapping pairs", len(overlaps)-i)
+			break
+		}
+		log.Printf("Overlap: %s intersects %s", o.First, o.Second)
 	}
 
-	// Generate payloads for "accepted" type orders
-	for i := activatedCount; i < totalOrders; i++ {
-		payloads = append(payloads, g.generatePayload(i, TypeAccepted))
+	if mode == config.VerifyStrict {
+		return fmt.Errorf("%w: %d pairs, first %s and %s", ErrOverlap, len(overlaps), overlaps[0].First, overlaps[0].Second)
+	}
+	return nil
+}
+
+// GenerateAll is Generate under the generator's original name
+func (g *Generator) GenerateAll() ([]OrderPayload, error) {
+	return g.Generate()
+}
+
+// CheckCapacity reports whether the boundary holds totalOrders polylines
+// once payload.overflow is applied. Unseeded runs are checked with seed 0.
+func CheckCapacity(cfg *config.Config, payloadData *config.PayloadData) error {
+	g := &Generator{config: cfg, payloadData: payloadData, seed: cfg.Simulation.DeriveSeed("payload")}
+	return g.planCapacity(cfg.Simulation.TotalOrders)
+}
+
+// maxWrapLayers bounds how many layers the wrap policy stacks
+const maxWrapLayers = 16
+
+// minShrinkScale is the smallest delta scale the shrink policy tries
+const minShrinkScale = 0.05
+
+// planCapacity checks that total polylines fit and prepares the overflow
+// policy when they do not
+func (g *Generator) planCapacity(total int) error {
+	switch pc := g.placementConfig(); {
+	case (pc.Strategy == config.PlacementRandom || pc.Strategy == config.PlacementPoisson) && len(g.payloadData.BoundarySet()) == 0:
+		return fmt.Errorf("%w: the %s placement strategy needs a boundary", ErrBoundaryExhausted, pc.Strategy)
+	case g.payloadData.Paths != nil && g.config.Payload.Overflow != "" && g.config.Payload.Overflow != config.OverflowFail:
+		return fmt.Errorf("%w: the %s overflow policy does not apply to path sources", ErrBoundaryExhausted, g.config.Payload.Overflow)
+	case g.payloadData.Paths == nil && pc.Strategy == config.PlacementRoute && len(g.payloadData.Route) < 2:
+		return fmt.Errorf("%w: the route placement strategy needs a route in the payload file", ErrBoundaryExhausted)
+	}
+
+	capacity := g.capacity(g.payloadData, total)
+	if capacity == Unbounded || capacity >= total {
+		return nil
+	}
+	if capacity == 0 && g.payloadData.Paths != nil {
+		return fmt.Errorf("%w: no route of %s lies inside the boundary", ErrBoundaryExhausted, g.payloadData.Paths.File)
+	}
+	if capacity == 0 {
+		return fmt.Errorf("%w: the first template does not fit inside the boundary", ErrBoundaryExhausted)
 	}
 
-	// Shuffle payloads for random distribution
-	//g.shuffle(payloads)
+	switch policy := g.config.Payload.Overflow; policy {
+	case config.OverflowWrap:
+		wrapped := g.layout()
+		wrapped.overflow = policy
+		if n := countPlacements(wrapped, total); n < total {
+			return fmt.Errorf("%w: %d wrap layers fit only %d polylines but totalOrders is %d",
+				ErrBoundaryExhausted, maxWrapLayers, n, total)
+		}
+		g.overflow = policy
+		log.Printf("Boundary fits %d polylines per layer, wrapping to fit %d orders", capacity, total)
+
+	case config.OverflowShrink:
+		for scale := 0.9; scale >= minShrinkScale; scale *= 0.9 {
+			scaled := *g.payloadData
+			scaled.Delta.Longitude *= scale
+			scaled.Delta.Latitude *= scale
+			if g.capacity(&scaled, total) >= total {
+				log.Printf("Boundary fits %d polylines, shrinking delta by %.3f to fit %d orders", capacity, scale, total)
+				g.payloadData = &scaled
+				g.delta = g.scale.degreeDelta(scaled.Delta)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: fits %d polylines but totalOrders is %d, even with the delta shrunk to %.0f%%",
+			ErrBoundaryExhausted, capacity, total, minShrinkScale*100)
 
-	return payloads
+	case config.OverflowReuse:
+		g.overflow = policy
+		log.Printf("Boundary fits %d polylines, reusing positions for %d orders", capacity, total)
+
+	default:
+		if !g.grid() {
+			return fmt.Errorf("%w: %s placement fits %d polylines but totalOrders is %d",
+				ErrBoundaryExhausted, g.placementName(), capacity, total)
+		}
+		return fmt.Errorf("%w: fits %d polylines but totalOrders is %d (set payload.overflow to wrap, shrink or reuse)",
+			ErrBoundaryExhausted, capacity, total)
+	}
+	return nil
 }
 
 // generatePayload creates a single order payload
-func (g *Generator) generatePayload(index int, orderType OrderType) OrderPayload {
+func (g *Generator) generatePayload(index int, orderType OrderType) (OrderPayload, error) {
 	// Generate unique order number with zero-padded index
 	orderNumber := fmt.Sprintf("%s%06d", g.config.Payload.OrderNumberPrefix, index+1)
 
@@ -103,56 +278,131 @@ func (g *Generator) generatePayload(index int, orderType OrderType) OrderPayload
 	log.Printf("Generating payload %d (order: %s, type: %s) - Position: row=%d, col=%d, direction=%d",
 		index, orderNumber, orderType, g.currentRow, g.currentCol, g.direction)
 
-	// Generate GeoJSON polyline with offset
-	geometry := g.generatePolyline(index)
+	// Generate GeoJSON geometry with offset
+	geometry, err := g.generatePolyline(index)
+	if err != nil {
+		return OrderPayload{}, err
+	}
 
-	// Format coordinates for easy copy-paste to GeoJSON
-	fmt.Printf("  \"coordinates\": [\n")
-	for i, coord := range geometry.Coordinates {
-		if i < len(geometry.Coordinates)-1 {
-			fmt.Printf("    [%.17f, %.17f],\n", coord[0], coord[1])
-		} else {
-			fmt.Printf("    [%.17f, %.17f]\n", coord[0], coord[1])
+	// Format coordinates for easy copy-paste to GeoJSON, one block per part
+	for _, line := range geometry.Lines() {
+		fmt.Printf("  \"coordinates\": [\n")
+		for i, coord := range line {
+			if i < len(line)-1 {
+				fmt.Printf("    [%.17f, %.17f],\n", coord[0], coord[1])
+			} else {
+				fmt.Printf("    [%.17f, %.17f]\n", coord[0], coord[1])
+			}
 		}
+		fmt.Printf("  ]\n")
 	}
-	fmt.Printf("  ]\n")
 
 	return OrderPayload{
 		OrderNumber:  orderNumber,
 		Location:     g.config.Payload.Location,
 		POCOrder:     g.config.Payload.POCOrder,
-		Timestamp:    time.Now(),
+		Timestamp:    g.timestamp(),
 		Type:         orderType,
 		CustomFields: customFields,
 		Geometry:     geometry,
+	}, nil
+}
+
+// generatePolyline places the next template, chosen by weight, at the next
+// zigzag position and returns it as a GeoJSON geometry. Running out of room
+// is reported as ErrBoundaryExhausted.
+func (g *Generator) generatePolyline(index int) (*GeoJSONGeometry, error) {
+	template := g.templates[g.picker.next()]
+	parts, ok := g.strategy.place(template)
+	if !ok {
+		return nil, fmt.Errorf("%w: room for only %d polylines", ErrBoundaryExhausted, index)
+	}
+
+	return newGeometry(template.Type, parts), nil
+}
+
+// place returns the next position, starting another layer when the current
+// one is full and the overflow policy allows it
+func (g *Generator) place(template config.Template) ([][][]float64, bool) {
+	for {
+		if parts, ok := g.nextPlacement(template); ok {
+			g.layerPlaced++
+			return parts, true
+		}
+
+		switch {
+		case g.overflow == config.OverflowWrap && g.layer+1 < maxWrapLayers:
+			g.startLayer(g.layer + 1)
+		case g.overflow == config.OverflowReuse && g.layerPlaced > 0:
+			g.startLayer(0)
+		default:
+			return nil, false
+		}
+	}
+}
+
+// startLayer resets the zigzag to the top-left corner of the given layer
+func (g *Generator) startLayer(layer int) {
+	g.layer = layer
+	g.layerPlaced = 0
+	g.currentRow = 0
+	g.currentCol = 0
+	g.direction = 1
+	g.maxColInRow = -1
+}
+
+// layerShift returns how far into the gap between placements a layer is
+// offset, as a fraction of the delta: 0, 1/2, 1/4, 3/4, 1/8, ... so every
+// new layer lands between the earlier ones
+func layerShift(layer int) float64 {
+	shift, unit := 0.0, 0.5
+	for ; layer > 0; layer >>= 1 {
+		if layer&1 == 1 {
+			shift += unit
+		}
+		unit /= 2
 	}
+	return shift
 }
 
-// generatePolyline creates a GeoJSON LineString with offset based on zigzag pattern
-func (g *Generator) generatePolyline(index int) *GeoJSONGeometry {
-	baseCoords := g.payloadData.BasePolyline.Coordinates
+// nextPlacement returns the template moved to the next zigzag position that
+// fits inside the boundary. It reports false once the rows have moved past
+// the boundary.
+func (g *Generator) nextPlacement(template config.Template) ([][][]float64, bool) {
+	minLat, bounded := g.boundaryMinLatitude()
+
+	shift := layerShift(g.layer)
 
 	// Try to place the polyline, adjusting position if needed
 	for {
 		// Calculate offset based on current position
-		lngOffset := g.payloadData.Delta.Longitude * float64(g.currentCol)
+		col := float64(g.currentCol) + shift
+		if g.hex && g.currentRow%2 == 1 {
+			col += 0.5
+		}
+		lngOffset := g.delta.Longitude * col
 
 		// Row offset includes the full polyline height + delta spacing
 		// This ensures rows are stacked like stairs, not overlapping
-		rowSpacing := g.polylineHeight + g.payloadData.Delta.Latitude
-		latOffset := -rowSpacing * float64(g.currentRow) // Negative to move down (south)
+		rowSpacing := g.rowSpacing()
+		latOffset := -rowSpacing*float64(g.currentRow) - g.delta.Latitude*shift // Negative to move down (south)
 
 		// Create candidate coordinates
-		coordinates := make([][]float64, len(baseCoords))
-		for i, coord := range baseCoords {
-			coordinates[i] = []float64{
-				coord[0] + lngOffset, // longitude
-				coord[1] + latOffset, // latitude
+		parts := make([][][]float64, len(template.Parts))
+		maxLat := math.Inf(-1)
+		for p, part := range template.Parts {
+			parts[p] = make([][]float64, len(part))
+			for i, coord := range part {
+				parts[p][i] = append([]float64{
+					coord[0] + lngOffset, // longitude
+					coord[1] + latOffset, // latitude
+				}, coord[2:]...) // Elevation rides along
+				maxLat = math.Max(maxLat, parts[p][i][1])
 			}
 		}
 
 		// Check if all points are within boundary
-		if g.isPolylineInBoundary(coordinates) {
+		if g.isGeometryInBoundary(template.Type, parts) {
 			// Valid position, track max column and advance
 			if g.currentCol > g.maxColInRow {
 				g.maxColInRow = g.currentCol
@@ -165,10 +415,12 @@ func (g *Generator) generatePolyline(index int) *GeoJSONGeometry {
 				g.currentCol-- // Going left, decrement
 			}
 
-			return &GeoJSONGeometry{
-				Type:        "LineString",
-				Coordinates: coordinates,
-			}
+			return parts, true
+		}
+
+		// The whole row is south of the boundary, or rows cannot move: no room left
+		if !bounded || maxLat < minLat || rowSpacing <= 0 {
+			return nil, false
 		}
 
 		// Doesn't fit, move to next row and flip direction
@@ -187,111 +439,255 @@ func (g *Generator) generatePolyline(index int) *GeoJSONGeometry {
 	}
 }
 
-// isPolylineInBoundary checks if all points of a polyline are within the boundary polygon
-func (g *Generator) isPolylineInBoundary(polyline [][]float64) bool {
-	boundary := g.payloadData.Boundary.Coordinates
+// hexRowFactor scales the gap between hex rows: staggered rows can sit
+// closer, as in a hexagonal packing
+var hexRowFactor = math.Sqrt(3) / 2
 
-	// If no boundary is configured, allow all positions
-	if len(boundary) == 0 {
-		return true
+// rowSpacing returns the vertical distance between layout rows
+func (g *Generator) rowSpacing() float64 {
+	if g.hex {
+		return g.polylineHeight + g.delta.Latitude*hexRowFactor
 	}
+	return g.polylineHeight + g.delta.Latitude
+}
 
-	// Use the first ring (exterior ring) of the polygon
-	exteriorRing := boundary[0]
+// placementConfig returns the configured placement, empty for scratch
+// generators without a config
+func (g *Generator) placementConfig() config.PlacementConfig {
+	if g.config == nil {
+		return config.PlacementConfig{}
+	}
+	return g.config.Payload.Placement
+}
 
-	// Check each point of the polyline
-	for _, point := range polyline {
-		if !g.isPointInPolygon(point[0], point[1], exteriorRing) {
-			return false
-		}
+// grid reports whether orders are laid out on the zigzag or hex grid
+func (g *Generator) grid() bool {
+	return g.payloadData.Paths == nil && g.placementConfig().Grid()
+}
+
+// placementName returns the placement strategy in effect
+func (g *Generator) placementName() string {
+	if g.payloadData.Paths != nil {
+		return "paths (" + g.payloadData.Paths.Format + ")"
+	}
+	if name := g.placementConfig().Strategy; name != "" {
+		return name
+	}
+	return config.PlacementZigzag
+}
+
+// boundaryBox returns the bounding box of every boundary polygon
+func (g *Generator) boundaryBox() box {
+	var exteriors [][]float64
+	for _, a := range g.areas {
+		exteriors = append(exteriors, a.rings[0]...)
+	}
+	return boxOf([][][]float64{exteriors})
+}
+
+// boundaryMinLatitude returns the southernmost latitude of all boundaries
+func (g *Generator) boundaryMinLatitude() (float64, bool) {
+	if len(g.areas) == 0 {
+		return 0, false
 	}
 
-	return true
+	minLat := math.Inf(1)
+	for _, a := range g.areas {
+		minLat = math.Min(minLat, a.minLatitude())
+	}
+	return minLat, true
 }
 
-// isPointInPolygon uses ray casting algorithm to check if point is inside polygon
-func (g *Generator) isPointInPolygon(lng, lat float64, polygon [][]float64) bool {
-	inside := false
-	j := len(polygon) - 1
+// Unbounded is returned by EstimateCapacity when no boundary is configured
+const Unbounded = -1
+
+// EstimateCapacity counts how many polylines the zigzag layout fits inside
+// the boundary, stopping at limit. It returns Unbounded without a boundary.
+// Overflow policies are not applied; this is the capacity of one layer.
+func EstimateCapacity(payloadData *config.PayloadData, limit int) int {
+	g := &Generator{payloadData: payloadData}
+	return g.capacity(payloadData, limit)
+}
+
+// Capacity is like EstimateCapacity for the placement strategy configured
+// in cfg. Unseeded runs are estimated with seed 0.
+func Capacity(cfg *config.Config, payloadData *config.PayloadData, limit int) int {
+	g := &Generator{config: cfg, payloadData: payloadData, seed: cfg.Simulation.DeriveSeed("payload")}
+	return g.capacity(payloadData, limit)
+}
+
+// capacity counts how many placements the configured strategy makes over
+// payloadData, stopping at limit. Grid strategies are Unbounded without a
+// boundary.
+func (g *Generator) capacity(payloadData *config.PayloadData, limit int) int {
+	if g.grid() && len(payloadData.BoundarySet()) == 0 {
+		return Unbounded
+	}
 
-	for i := 0; i < len(polygon); i++ {
-		xi, yi := polygon[i][0], polygon[i][1]
-		xj, yj := polygon[j][0], polygon[j][1]
+	scratch := &Generator{config: g.config, payloadData: payloadData, seed: g.seed}
+	return countPlacements(scratch.layout(), limit)
+}
+
+// layout returns a fresh generator over the same geometry and strategy,
+// starting at the top-left corner and the first template. Scratch layouts
+// count placements without disturbing g.
+func (g *Generator) layout() *Generator {
+	templates := g.payloadData.TemplateSet()
+	if g.payloadData.Paths != nil {
+		// Routes are drawn, not moved: the template only names the type
+		templates = []config.Template{{Name: "path", Type: config.GeometryLineString}}
+	}
+	weights := make([]int, len(templates))
+	for i, t := range templates {
+		weights[i] = t.EffectiveWeight()
+	}
 
-		intersect := ((yi > lat) != (yj > lat)) &&
-			(lng < (xj-xi)*(lat-yi)/(yj-yi)+xi)
+	var reference []float64
+	if len(templates[0].Parts) > 0 && len(templates[0].Parts[0]) > 0 {
+		reference = templates[0].Parts[0][0]
+	}
+	spacing := newScale(g.payloadData.Delta, reference)
+
+	fresh := &Generator{
+		config:         g.config,
+		payloadData:    g.payloadData,
+		seed:           g.seed,
+		hex:            g.placementConfig().Strategy == config.PlacementHex,
+		scale:          spacing,
+		delta:          spacing.degreeDelta(g.payloadData.Delta),
+		direction:      1, // Start moving right
+		maxColInRow:    -1,
+		polylineHeight: templatesHeight(templates),
+		templates:      templates,
+		picker:         newSmoothWeighted(weights),
+		areas:          boundaryAreas(g.payloadData),
+	}
+	fresh.strategy = newPlacementStrategy(fresh)
+	return fresh
+}
 
-		if intersect {
-			inside = !inside
+// countPlacements places polylines until the layout is exhausted or limit is reached
+func countPlacements(g *Generator, limit int) int {
+	count := 0
+	for count < limit {
+		if _, ok := g.strategy.place(g.templates[g.picker.next()]); !ok {
+			break
 		}
+		count++
+	}
+	return count
+}
+
+// isGeometryInBoundary checks that a geometry lies entirely within one
+// boundary polygon, outside its holes and without any segment leaving it
+func (g *Generator) isGeometryInBoundary(geometryType string, parts [][][]float64) bool {
+	// If no boundary is configured, allow all positions
+	if len(g.areas) == 0 {
+		return true
+	}
 
-		j = i
+	for _, a := range g.areas {
+		if a.containsGeometry(geometryType, parts) {
+			return true
+		}
 	}
+	return false
+}
+
+// isPolylineInBoundary checks if a polyline is within the boundary
+func (g *Generator) isPolylineInBoundary(polyline [][]float64) bool {
+	return g.isGeometryInBoundary(config.GeometryLineString, [][][]float64{polyline})
+}
+
+// isPointInPolygon uses ray casting algorithm to check if point is inside polygon
+func (g *Generator) isPointInPolygon(lng, lat float64, polygon [][]float64) bool {
+	return pointInRing(lng, lat, polygon)
+}
 
-	return inside
+// Reference is a template where it sits in the payload file, drawn
+// alongside the generated orders
+type Reference struct {
+	Name     string           `json:"name"`
+	Geometry *GeoJSONGeometry `json:"geometry"`
 }
 
-// shuffle randomizes the order of payloads
-func (g *Generator) shuffle(payloads []OrderPayload) {
-	g.rng.Shuffle(len(payloads), func(i, j int) {
-		payloads[i], payloads[j] = payloads[j], payloads[i]
-	})
+// References returns the templates in effect. Path sources have none.
+func (g *Generator) References() []Reference {
+	var refs []Reference
+	for _, t := range g.templates {
+		if len(t.Parts) == 0 {
+			continue
+		}
+		name := "Base Polyline (Row 0, Col 0)"
+		if len(g.payloadData.Templates) > 0 {
+			name = fmt.Sprintf("Template %s (Row 0, Col 0)", t.Name)
+		}
+		refs = append(refs, Reference{Name: name, Geometry: newGeometry(t.Type, t.Parts)})
+	}
+	return refs
 }
 
 // DumpGeoJSON outputs all payloads and boundary as a GeoJSON FeatureCollection
 func (g *Generator) DumpGeoJSON(payloads []OrderPayload) {
 	features := []map[string]interface{}{}
 
-	// Add boundary polygon as first feature
-	if len(g.payloadData.Boundary.Coordinates) > 0 {
+	// Add boundary polygons as the first features
+	for _, b := range g.payloadData.BoundarySet() {
+		name := "Boundary"
+		if b.Name != config.DefaultBoundaryName {
+			name = "Boundary " + b.Name
+		}
 		boundaryFeature := map[string]interface{}{
 			"type": "Feature",
 			"properties": map[string]interface{}{
-				"name":         "Boundary",
+				"name":         name,
 				"stroke":       "#ff0000",
 				"stroke-width": 2,
 				"fill":         "#ff0000",
 				"fill-opacity": 0.1,
 			},
 			"geometry": map[string]interface{}{
-				"type":        "Polygon",
-				"coordinates": g.payloadData.Boundary.Coordinates,
+				"type":        config.GeometryMultiPolygon,
+				"coordinates": b.AllPolygons(),
 			},
 		}
 		features = append(features, boundaryFeature)
 	}
 
-	// Add base polyline for reference
-	baseFeature := map[string]interface{}{
-		"type": "Feature",
-		"properties": map[string]interface{}{
-			"name":         "Base Polyline (Row 0, Col 0)",
-			"stroke":       "#0000ff",
-			"stroke-width": 3,
-		},
-		"geometry": map[string]interface{}{
-			"type":        "LineString",
-			"coordinates": g.payloadData.BasePolyline.Coordinates,
-		},
+	// Add the templates for reference
+	for _, ref := range g.References() {
+		baseFeature := map[string]interface{}{
+			"type": "Feature",
+			"properties": map[string]interface{}{
+				"name":         ref.Name,
+				"stroke":       "#0000ff",
+				"stroke-width": 3,
+			},
+			"geometry": ref.Geometry,
+		}
+		features = append(features, baseFeature)
 	}
-	features = append(features, baseFeature)
 
 	// Add all generated polylines
 	for i, payload := range payloads {
 		if payload.Geometry != nil {
+			properties := map[string]interface{}{
+				"orderNumber":  payload.OrderNumber,
+				"index":        i,
+				"type":         string(payload.Type),
+				"stroke":       TypeColor(payload.Type),
+				"stroke-width": 2,
+			}
+			// coordTimes is flat for single-part geometries, as GPX converters write it
+			if times := payload.CoordTimes; len(times) == 1 {
+				properties["coordTimes"] = times[0]
+			} else if len(times) > 1 {
+				properties["coordTimes"] = times
+			}
 			feature := map[string]interface{}{
-				"type": "Feature",
-				"properties": map[string]interface{}{
-					"orderNumber":  payload.OrderNumber,
-					"index":        i,
-					"type":         string(payload.Type),
-					"stroke":       getColorForType(payload.Type),
-					"stroke-width": 2,
-				},
-				"geometry": map[string]interface{}{
-					"type":        payload.Geometry.Type,
-					"coordinates": payload.Geometry.Coordinates,
-				},
+				"type":       "Feature",
+				"properties": properties,
+				"geometry":   payload.Geometry,
 			}
 			features = append(features, feature)
 		}
@@ -326,13 +722,35 @@ func (g *Generator) DumpGeoJSON(payloads []OrderPayload) {
 	}
 
 	log.Printf("✓ GeoJSON saved to: %s", filename)
-	log.Printf("  View at: https://geojson.io (drag and drop the file)")
+	log.Printf("  View offline with payload.exports: [html], or drop the file on a GeoJSON viewer")
 }
 
-// getColorForType returns a color based on order type
-func getColorForType(orderType OrderType) string {
+// TypeColor returns a color based on order type
+func TypeColor(orderType OrderType) string {
 	if orderType == TypeActivate {
 		return "#00ff00" // Green for activate
 	}
 	return "#ffaa00" // Orange for accepted
 }
+
+// stateColors colors orders by their final state
+var stateColors = map[OrderState]string{
+	StateEnded:         "#2e7d32",
+	StateCancelled:     "#1565c0",
+	StateFailed:        "#d32f2f",
+	StateActivated:     "#f9a825",
+	StateAccepted:      "#f9a825",
+	StatePendingEnd:    "#f9a825",
+	StatePendingCancel: "#f9a825",
+	StateCreated:       "#757575",
+}
+
+// StateColor returns a color based on the final state of an order: green
+// for ended, blue for cancelled, red for failed, amber while in flight and
+// grey for created or never processed
+func StateColor(state OrderState) string {
+	if color, ok := stateColors[state]; ok {
+		return color
+	}
+	return "#9e9e9e"
+}
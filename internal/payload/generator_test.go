@@ -1,9 +1,12 @@
 package payload
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"reflect"
 	"testing"
+	"time"
 
 	"gameday-sim/internal/config"
 )
@@ -396,10 +399,16 @@ func TestGeneratePolyline_Offset(t *testing.T) {
 	gen := NewGenerator(cfg, payloadData)
 
 	// Generate first polyline at (0, 0)
-	first := gen.generatePolyline(0)
+	first, err := gen.generatePolyline(0)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Generate second polyline - should be at (0, 1) since direction is right
-	second := gen.generatePolyline(1)
+	second, err := gen.generatePolyline(1)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if first == nil || second == nil {
 		t.Fatal("Generated polylines should not be nil")
@@ -439,7 +448,10 @@ func TestGeneratePolyline_GeometryType(t *testing.T) {
 	cfg, payloadData := createTestConfigWithGeo()
 	gen := NewGenerator(cfg, payloadData)
 
-	geom := gen.generatePolyline(0)
+	geom, err := gen.generatePolyline(0)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if geom.Type != "LineString" {
 		t.Errorf("Geometry type = %s, expected LineString", geom.Type)
@@ -578,7 +590,10 @@ func TestGenerateAll_TypeDistribution(t *testing.T) {
 	cfg, payloadData := createTestConfigWithGeo()
 	gen := NewGenerator(cfg, payloadData)
 
-	payloads := gen.GenerateAll()
+	payloads, err := gen.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	activateCount := 0
 	acceptedCount := 0
@@ -606,7 +621,10 @@ func TestGenerateAll_GeometryPresent(t *testing.T) {
 	cfg, payloadData := createTestConfigWithGeo()
 	gen := NewGenerator(cfg, payloadData)
 
-	payloads := gen.GenerateAll()
+	payloads, err := gen.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	for i, p := range payloads {
 		if p.Geometry == nil {
@@ -629,7 +647,10 @@ func TestGenerateAll_AllWithinBoundary(t *testing.T) {
 	cfg, payloadData := createTestConfigWithGeo()
 	gen := NewGenerator(cfg, payloadData)
 
-	payloads := gen.GenerateAll()
+	payloads, err := gen.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	// Create a fresh generator for validation
 	validationGen := NewGenerator(cfg, payloadData)
@@ -651,7 +672,10 @@ func TestGenerateAll_NonOverlapping(t *testing.T) {
 	cfg, payloadData := createTestConfigWithGeo()
 	gen := NewGenerator(cfg, payloadData)
 
-	payloads := gen.GenerateAll()
+	payloads, err := gen.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	// Create a set to track unique positions based on first coordinate
 	type coordKey struct {
@@ -681,7 +705,10 @@ func TestGenerateAll_UniqueOrderNumbers(t *testing.T) {
 	cfg, payloadData := createTestConfigWithGeo()
 	gen := NewGenerator(cfg, payloadData)
 
-	payloads := gen.GenerateAll()
+	payloads, err := gen.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	orderNumbers := make(map[string]int)
 	for i, p := range payloads {
@@ -697,7 +724,10 @@ func TestOrderNumberFormat(t *testing.T) {
 	cfg, payloadData := createTestConfigWithGeo()
 	gen := NewGenerator(cfg, payloadData)
 
-	payloads := gen.GenerateAll()
+	payloads, err := gen.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	for i, p := range payloads {
 		expected := fmt.Sprintf("ORD-TEST-%06d", i+1)
@@ -712,7 +742,10 @@ func TestCoordinateRanges(t *testing.T) {
 	cfg, payloadData := createTestConfigWithGeo()
 	gen := NewGenerator(cfg, payloadData)
 
-	payloads := gen.GenerateAll()
+	payloads, err := gen.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	for i, p := range payloads {
 		if p.Geometry == nil {
@@ -734,3 +767,75 @@ func TestCoordinateRanges(t *testing.T) {
 		}
 	}
 }
+
+// TestGenerateAll_SeededRunsMatch tests that a seed and epoch make generation reproducible
+func TestGenerateAll_SeededRunsMatch(t *testing.T) {
+	cfg, payloadData := createTestConfigWithGeo()
+	cfg.Simulation.Seed = 42
+	cfg.Simulation.Epoch = time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	first, err := NewGenerator(cfg, payloadData).GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
+	second, err := NewGenerator(cfg, payloadData).GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
+
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Seeded runs produced different payloads")
+	}
+	for _, p := range first {
+		if !p.Timestamp.Equal(cfg.Simulation.Epoch) {
+			t.Errorf("Payload %s timestamp = %s, expected epoch %s", p.OrderNumber, p.Timestamp, cfg.Simulation.Epoch)
+		}
+	}
+}
+
+func TestTranslateKeepsElevation(t *testing.T) {
+	moved := translate([][][]float64{{{1, 2}, {3, 4, 150}}}, 0.5, -0.5)
+	want := [][][]float64{{{1.5, 1.5}, {3.5, 3.5, 150}}}
+	if !reflect.DeepEqual(moved, want) {
+		t.Errorf("translate() = %v, want %v", moved, want)
+	}
+}
+
+// TestGenerateAll_KeepsElevation tests that the default zigzag placement
+// keeps the elevation of a 3D template
+func TestGenerateAll_KeepsElevation(t *testing.T) {
+	cfg, payloadData := createTestConfigWithGeo()
+	for i := range payloadData.BasePolyline.Coordinates {
+		payloadData.BasePolyline.Coordinates[i] = append(payloadData.BasePolyline.Coordinates[i], 150)
+	}
+	gen := NewGenerator(cfg, payloadData)
+
+	payloads, err := gen.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
+
+	for i, p := range payloads {
+		for j, coord := range p.Geometry.Coordinates {
+			if len(coord) != 3 || coord[2] != 150 {
+				t.Fatalf("Payload %d position %d = %v, expected elevation 150", i, j, coord)
+			}
+		}
+	}
+}
+
+// TestGeneratePolyline_Exhausted tests that running out of room is an error, not a panic
+func TestGeneratePolyline_Exhausted(t *testing.T) {
+	cfg, payloadData := createSmallBoundaryConfig()
+	gen := NewGenerator(cfg, payloadData)
+
+	for i := 0; i < 100000; i++ {
+		if _, err := gen.generatePolyline(i); err != nil {
+			if !errors.Is(err, ErrBoundaryExhausted) {
+				t.Fatalf("expected ErrBoundaryExhausted, got %v", err)
+			}
+			return
+		}
+	}
+	t.Fatal("expected the small boundary to run out of room")
+}
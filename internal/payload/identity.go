@@ -0,0 +1,31 @@
+package payload
+
+import "gameday-sim/internal/config"
+
+// AssignIdentities sets the Identity of each payload from the pool, either
+// round-robin or in proportion to identity weights. Weighted assignment uses
+// smooth weighted round-robin, so it is deterministic and spreads each
+// identity's orders evenly through the run.
+func AssignIdentities(payloads []OrderPayload, identities config.IdentityConfig) {
+	pool := identities.Pool
+	if len(pool) == 0 {
+		return
+	}
+
+	if identities.Assignment != config.AssignWeighted {
+		for i := range payloads {
+			payloads[i].Identity = pool[i%len(pool)].Name
+		}
+		return
+	}
+
+	weights := make([]int, len(pool))
+	for i, id := range pool {
+		weights[i] = id.EffectiveWeight()
+	}
+
+	picker := newSmoothWeighted(weights)
+	for i := range payloads {
+		payloads[i].Identity = pool[picker.next()].Name
+	}
+}
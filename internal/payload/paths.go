@@ -0,0 +1,221 @@
+package payload
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"gameday-sim/internal/config"
+)
+
+// pathPlacement gives every order its own route from the payload's path
+// source instead of moving a template. File routes are handed out once each
+// in a seeded shuffle; on a road graph the shortest path between two random
+// nodes is computed. Routes leaving the boundary or already handed out are
+// skipped.
+type pathPlacement struct {
+	g        *Generator
+	rng      *rand.Rand
+	attempts int
+	used     map[string]bool
+
+	routes [][][]float64 // Fixed routes from a GeoJSON or GPX file
+	order  []int         // Shuffled indexes into routes
+	next   int
+
+	graph *roadGraph
+}
+
+// newPathPlacement returns the strategy drawing routes from g's path source
+func newPathPlacement(g *Generator) *pathPlacement {
+	p := &pathPlacement{
+		g:        g,
+		rng:      rand.New(rand.NewSource(g.seed)),
+		attempts: g.placementConfig().MaxAttempts,
+		used:     make(map[string]bool),
+	}
+	if p.attempts == 0 {
+		p.attempts = defaultMaxAttempts
+	}
+
+	source := g.payloadData.Paths
+	if source.Graph != nil {
+		p.graph = newRoadGraph(source.Graph)
+	} else {
+		p.routes = source.Routes
+		p.order = p.rng.Perm(len(source.Routes))
+	}
+	return p
+}
+
+// place implements placementStrategy; the template only names the geometry type
+func (p *pathPlacement) place(_ config.Template) ([][][]float64, bool) {
+	if p.graph != nil {
+		return p.shortestRoute()
+	}
+
+	for p.next < len(p.order) {
+		route := p.routes[p.order[p.next]]
+		p.next++
+		if parts, ok := p.claim(fmt.Sprint(route), "", route); ok {
+			return parts, true
+		}
+	}
+	return nil, false
+}
+
+// shortestRoute picks random start and end nodes until the shortest path
+// between them is a new route inside the boundary
+func (p *pathPlacement) shortestRoute() ([][][]float64, bool) {
+	n := len(p.graph.positions)
+	if n < 2 {
+		return nil, false
+	}
+
+	for i := 0; i < p.attempts; i++ {
+		from, to := p.rng.Intn(n), p.rng.Intn(n)
+		if from == to {
+			continue
+		}
+		nodes := p.graph.shortestPath(from, to)
+		if len(nodes) < 2 {
+			continue
+		}
+
+		route := make([][]float64, len(nodes))
+		ids := make([]string, len(nodes))
+		reversed := make([]string, len(nodes))
+		for k, node := range nodes {
+			route[k] = p.graph.positions[node]
+			ids[k] = p.graph.ids[node]
+			reversed[len(nodes)-1-k] = p.graph.ids[node]
+		}
+		if parts, ok := p.claim(strings.Join(ids, "\x00"), strings.Join(reversed, "\x00"), route); ok {
+			return parts, true
+		}
+	}
+	return nil, false
+}
+
+// claim hands out a route that was not handed out before, under either of
+// its keys, and lies inside the boundary
+func (p *pathPlacement) claim(key, reverseKey string, route [][]float64) ([][][]float64, bool) {
+	if p.used[key] || (reverseKey != "" && p.used[reverseKey]) {
+		return nil, false
+	}
+	parts := translate([][][]float64{route}, 0, 0)
+	if !p.g.isGeometryInBoundary(config.GeometryLineString, parts) {
+		return nil, false
+	}
+
+	p.used[key] = true
+	if reverseKey != "" {
+		p.used[reverseKey] = true
+	}
+	return parts, true
+}
+
+// roadGraph is a config.RoadGraph indexed for routing. Nodes are numbered
+// in ID order so seeded runs pick the same nodes.
+type roadGraph struct {
+	ids       []string
+	positions [][]float64
+	links     [][]roadLink
+}
+
+// roadLink is a travellable edge to another node
+type roadLink struct {
+	to     int
+	length float64 // Meters
+}
+
+// newRoadGraph indexes the nodes and edges of a road graph
+func newRoadGraph(rg *config.RoadGraph) *roadGraph {
+	g := &roadGraph{}
+	index := make(map[string]int, len(rg.Nodes))
+	for id := range rg.Nodes {
+		g.ids = append(g.ids, id)
+	}
+	sort.Strings(g.ids)
+	for i, id := range g.ids {
+		index[id] = i
+		g.positions = append(g.positions, rg.Nodes[id])
+	}
+
+	g.links = make([][]roadLink, len(g.ids))
+	for _, e := range rg.Edges {
+		from, fromOK := index[e.From]
+		to, toOK := index[e.To]
+		if !fromOK || !toOK || from == to {
+			continue
+		}
+		length := greatCircleDistance(g.positions[from], g.positions[to])
+		g.links[from] = append(g.links[from], roadLink{to: to, length: length})
+		if !e.OneWay {
+			g.links[to] = append(g.links[to], roadLink{to: from, length: length})
+		}
+	}
+	return g
+}
+
+// shortestPath returns the nodes of the shortest path from one node to
+// another with Dijkstra's algorithm, or nil when to cannot be reached
+func (g *roadGraph) shortestPath(from, to int) []int {
+	dist := make([]float64, len(g.ids))
+	prev := make([]int, len(g.ids))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[from] = 0
+
+	queue := &nodeQueue{{node: from}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queuedNode)
+		if current.node == to {
+			break
+		}
+		if current.dist > dist[current.node] {
+			continue
+		}
+		for _, link := range g.links[current.node] {
+			if d := current.dist + link.length; d < dist[link.to] {
+				dist[link.to] = d
+				prev[link.to] = current.node
+				heap.Push(queue, queuedNode{node: link.to, dist: d})
+			}
+		}
+	}
+
+	if math.IsInf(dist[to], 1) {
+		return nil
+	}
+	var path []int
+	for node := to; node != -1; node = prev[node] {
+		path = append([]int{node}, path...)
+	}
+	return path
+}
+
+// queuedNode is a node waiting in the Dijkstra queue with its tentative distance
+type queuedNode struct {
+	node int
+	dist float64
+}
+
+// nodeQueue is a min-heap of queued nodes by distance
+type nodeQueue []queuedNode
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(queuedNode)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
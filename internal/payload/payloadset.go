@@ -0,0 +1,159 @@
+package payload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+// PayloadSetVersion is the payload set file format written by Save. Bump it
+// whenever OrderPayload, Batch or SetBoundary change in a way older files
+// cannot be read back as.
+const PayloadSetVersion = 2
+
+// ErrPayloadSetVersion is returned by LoadPayloadSet for files written in a
+// format this build does not read
+var ErrPayloadSetVersion = errors.New("unsupported payload set version")
+
+// PayloadSet is a generated, batched set of orders saved by the generate
+// command, so that the same orders can be reviewed before a game day and
+// replayed in any environment
+type PayloadSet struct {
+	Version      int           `json:"version"`
+	GeneratedAt  time.Time     `json:"generatedAt"`
+	Seed         int64         `json:"seed"`         // Simulation seed the set was generated with
+	PayloadFile  string        `json:"payloadFile"`  // Payload geometry file the set was generated from
+	Distribution string        `json:"distribution"` // Strategy that assigned the batches
+	BatchSize    int           `json:"batchSize"`
+	Boundaries   []SetBoundary `json:"boundaries,omitempty"`
+	References   []Reference   `json:"references,omitempty"`
+	Batches      []Batch       `json:"batches"`
+}
+
+// SetBoundary is a named boundary as saved in a payload set
+type SetBoundary struct {
+	Name     string          `json:"name"`
+	Polygons [][][][]float64 `json:"polygons"` // Each polygon is an exterior ring followed by its holes
+}
+
+// newSetBoundaries converts boundaries for saving in a set
+func newSetBoundaries(boundaries []config.PolygonBoundary) []SetBoundary {
+	var set []SetBoundary
+	for _, b := range boundaries {
+		set = append(set, SetBoundary{Name: b.Name, Polygons: b.AllPolygons()})
+	}
+	return set
+}
+
+// BoundarySet returns the boundaries the orders of the set were laid out in
+func (s *PayloadSet) BoundarySet() []config.PolygonBoundary {
+	var boundaries []config.PolygonBoundary
+	for _, b := range s.Boundaries {
+		boundaries = append(boundaries, config.PolygonBoundary{Name: b.Name, Polygons: b.Polygons})
+	}
+	return boundaries
+}
+
+// NewPayloadSet wraps batches generated with cfg, along with the boundaries
+// and templates they were laid out in for later exports
+func NewPayloadSet(cfg *config.Config, g *Generator, batches []Batch) *PayloadSet {
+	distribution := cfg.Simulation.Distribution
+	if distribution == "" {
+		distribution = string(DistributeSequential)
+	}
+	return &PayloadSet{
+		Version:      PayloadSetVersion,
+		GeneratedAt:  time.Now().UTC(),
+		Seed:         cfg.Simulation.Seed,
+		PayloadFile:  cfg.Payload.DataFile(),
+		Distribution: distribution,
+		BatchSize:    cfg.Simulation.BatchSize,
+		Boundaries:   newSetBoundaries(g.payloadData.BoundarySet()),
+		References:   g.References(),
+		Batches:      batches,
+	}
+}
+
+// Payloads returns every order in the set in batch order
+func (s *PayloadSet) Payloads() []OrderPayload {
+	var payloads []OrderPayload
+	for _, batch := range s.Batches {
+		payloads = append(payloads, batch.Payloads...)
+	}
+	return payloads
+}
+
+// CheckIdentities reports orders placed as identities missing from the
+// pool, which happens when a set moves to an environment configured with
+// other identities
+func (s *PayloadSet) CheckIdentities(identities config.IdentityConfig) error {
+	known := make(map[string]bool, len(identities.Pool))
+	for _, id := range identities.Pool {
+		known[id.Name] = true
+	}
+
+	var missing []string
+	for _, p := range s.Payloads() {
+		if p.Identity != "" && !known[p.Identity] && !slices.Contains(missing, p.Identity) {
+			missing = append(missing, p.Identity)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("payload set uses identities missing from identities.pool: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Save writes the set as indented JSON, creating the directory if needed
+func (s *PayloadSet) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload set: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write payload set: %w", err)
+	}
+	return nil
+}
+
+// LoadPayloadSet reads a set written by Save and checks its version and
+// batches
+func LoadPayloadSet(path string) (*PayloadSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload set: %w", err)
+	}
+
+	var set PayloadSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse payload set %s: %w", path, err)
+	}
+	if set.Version != PayloadSetVersion {
+		return nil, fmt.Errorf("%w: %s is version %d, expected %d", ErrPayloadSetVersion, path, set.Version, PayloadSetVersion)
+	}
+	if err := ValidateBatches(set.Batches); err != nil {
+		return nil, fmt.Errorf("invalid payload set %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range set.Payloads() {
+		if seen[p.OrderNumber] {
+			return nil, fmt.Errorf("invalid payload set %s: duplicate order number %q", path, p.OrderNumber)
+		}
+		seen[p.OrderNumber] = true
+	}
+	return &set, nil
+}
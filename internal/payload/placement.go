@@ -0,0 +1,246 @@
+package payload
+
+import (
+	"math"
+	"math/rand"
+
+	"gameday-sim/internal/config"
+)
+
+// placementStrategy chooses where each order's template is drawn. place
+// returns the template's parts moved to the next position, or false once no
+// position is left. The zigzag crawl is implemented by Generator itself.
+type placementStrategy interface {
+	place(template config.Template) ([][][]float64, bool)
+}
+
+// defaultMaxAttempts bounds random samples per order when maxAttempts is not set
+const defaultMaxAttempts = 1000
+
+// poissonCandidates is the number of candidates tried around an active
+// placement before it is retired, as in Bridson's algorithm
+const poissonCandidates = 30
+
+// newPlacementStrategy returns the strategy configured for g, which is
+// g itself for the grid strategies. A path source overrides the strategy.
+func newPlacementStrategy(g *Generator) placementStrategy {
+	if g.payloadData.Paths != nil {
+		return newPathPlacement(g)
+	}
+	pc := g.placementConfig()
+	if pc.Grid() {
+		return g
+	}
+
+	s := &scatter{
+		g:          g,
+		rng:        rand.New(rand.NewSource(g.seed)),
+		scale:      g.scale,
+		separation: pc.MinSeparation,
+		attempts:   pc.MaxAttempts,
+	}
+	if s.separation == 0 {
+		s.separation = math.Max(g.payloadData.Delta.Longitude, g.payloadData.Delta.Latitude)
+	}
+	if s.attempts == 0 {
+		s.attempts = defaultMaxAttempts
+	}
+
+	switch pc.Strategy {
+	case config.PlacementRandom:
+		return &randomPlacement{s}
+	case config.PlacementPoisson:
+		return &poissonPlacement{scatter: s}
+	default:
+		return &routePlacement{scatter: s, route: g.payloadData.Route}
+	}
+}
+
+// box is an axis-aligned bounding box: minX, minY, maxX, maxY
+type box [4]float64
+
+// boxOf returns the bounding box of every position of every part
+func boxOf(parts [][][]float64) box {
+	minX, minY, maxX, maxY := bounds(flatten(parts))
+	return box{minX, minY, maxX, maxY}
+}
+
+// scatter holds what the free-form strategies share: boundary checks and
+// the boxes already placed, which must stay separation apart. Separation is
+// in the unit of the payload delta; scale converts it to degrees.
+type scatter struct {
+	g          *Generator
+	rng        *rand.Rand
+	scale      scale
+	separation float64
+	attempts   int
+	placed     []box
+}
+
+// try moves the template by (dx, dy) and keeps it if it fits the boundary
+// and keeps its distance from earlier placements
+func (s *scatter) try(template config.Template, dx, dy float64) ([][][]float64, bool) {
+	parts := translate(template.Parts, dx, dy)
+	if !s.g.isGeometryInBoundary(template.Type, parts) {
+		return nil, false
+	}
+
+	b := boxOf(parts)
+	for _, p := range s.placed {
+		gapX := math.Max(p[0]-b[2], b[0]-p[2]) * s.scale.perDegree[0]
+		gapY := math.Max(p[1]-b[3], b[1]-p[3]) * s.scale.perDegree[1]
+		if gapX < s.separation && gapY < s.separation {
+			return nil, false
+		}
+	}
+
+	s.placed = append(s.placed, b)
+	return parts, true
+}
+
+// sample moves the template to a uniformly random position whose bounding
+// box lies within the boundary's
+func (s *scatter) sample(template config.Template) ([][][]float64, bool) {
+	area := s.g.boundaryBox()
+	t := boxOf(template.Parts)
+	spanX := (area[2] - area[0]) - (t[2] - t[0])
+	spanY := (area[3] - area[1]) - (t[3] - t[1])
+	if spanX < 0 || spanY < 0 {
+		return nil, false
+	}
+
+	for i := 0; i < s.attempts; i++ {
+		dx := area[0] - t[0] + s.rng.Float64()*spanX
+		dy := area[1] - t[1] + s.rng.Float64()*spanY
+		if parts, ok := s.try(template, dx, dy); ok {
+			return parts, true
+		}
+	}
+	return nil, false
+}
+
+// randomPlacement draws uniform positions with rejection sampling
+type randomPlacement struct {
+	*scatter
+}
+
+// place implements placementStrategy
+func (r *randomPlacement) place(template config.Template) ([][][]float64, bool) {
+	return r.sample(template)
+}
+
+// poissonPlacement spreads placements Poisson-disk style: each new one is
+// tried in a ring around a random earlier placement, giving an even but
+// irregular spread. It starts where the template sits.
+type poissonPlacement struct {
+	*scatter
+	active [][2]float64 // Offsets that may still have room around them
+	seeded bool
+}
+
+// place implements placementStrategy
+func (p *poissonPlacement) place(template config.Template) ([][][]float64, bool) {
+	if !p.seeded {
+		p.seeded = true
+		parts, ok := p.try(template, 0, 0)
+		if !ok {
+			parts, ok = p.sample(template)
+		}
+		if ok {
+			p.activate(template, parts)
+		}
+		return parts, ok
+	}
+
+	// Neighbours must clear the template itself plus the separation, so the
+	// ring is stretched to the template's width and height
+	t := boxOf(template.Parts)
+	radiusX := t[2] - t[0] + p.scale.lng(p.separation)
+	radiusY := t[3] - t[1] + p.scale.lat(p.separation)
+
+	for len(p.active) > 0 {
+		i := p.rng.Intn(len(p.active))
+		center := p.active[i]
+		for k := 0; k < poissonCandidates; k++ {
+			angle := p.rng.Float64() * 2 * math.Pi
+			scale := 1 + p.rng.Float64()
+			dx := center[0] + scale*radiusX*math.Cos(angle)
+			dy := center[1] + scale*radiusY*math.Sin(angle)
+			if parts, ok := p.try(template, dx, dy); ok {
+				p.active = append(p.active, [2]float64{dx, dy})
+				return parts, true
+			}
+		}
+		p.active = append(p.active[:i], p.active[i+1:]...)
+	}
+	return nil, false
+}
+
+// activate records the offset of a placement as a centre for later candidates
+func (p *poissonPlacement) activate(template config.Template, parts [][][]float64) {
+	origin := template.Parts[0][0]
+	placed := parts[0][0]
+	p.active = append(p.active, [2]float64{placed[0] - origin[0], placed[1] - origin[1]})
+}
+
+// routePlacement walks the seed route, anchoring the template's first
+// position every separation along it and skipping spots that do not fit
+type routePlacement struct {
+	*scatter
+	route    [][]float64
+	segment  int     // Current route segment
+	progress float64 // Distance travelled along the current segment
+	started  bool
+}
+
+// place implements placementStrategy
+func (r *routePlacement) place(template config.Template) ([][][]float64, bool) {
+	origin := template.Parts[0][0]
+	for {
+		anchor, ok := r.advance()
+		if !ok {
+			return nil, false
+		}
+		if parts, ok := r.try(template, anchor[0]-origin[0], anchor[1]-origin[1]); ok {
+			return parts, true
+		}
+	}
+}
+
+// advance returns the next anchor point, separation further along the route
+func (r *routePlacement) advance() ([]float64, bool) {
+	if len(r.route) < 2 {
+		return nil, false
+	}
+	if !r.started {
+		r.started = true
+		return r.route[0], true
+	}
+
+	step := r.separation
+	for r.segment < len(r.route)-1 {
+		a, b := r.route[r.segment], r.route[r.segment+1]
+		length := r.scale.distance(a, b)
+		if r.progress+step <= length {
+			r.progress += step
+			f := r.progress / length
+			return []float64{a[0] + f*(b[0]-a[0]), a[1] + f*(b[1]-a[1])}, true
+		}
+		step -= length - r.progress
+		r.segment++
+		r.progress = 0
+	}
+	return nil, false
+}
+
+// translate returns a copy of parts moved by (dx, dy)
+func translate(parts [][][]float64, dx, dy float64) [][][]float64 {
+	moved := make([][][]float64, len(parts))
+	for p, part := range parts {
+		moved[p] = make([][]float64, len(part))
+		for i, coord := range part {
+			moved[p][i] = append([]float64{coord[0] + dx, coord[1] + dy}, coord[2:]...) // Elevation rides along
+		}
+	}
+	return moved
+}
@@ -0,0 +1,122 @@
+package payload
+
+import (
+	"math"
+
+	"gameday-sim/internal/config"
+)
+
+// WGS84 ellipsoid and UTM constants
+const (
+	wgs84A       = 6378137.0
+	wgs84F       = 1 / 298.257223563
+	utmScale     = 0.9996
+	earthRadiusM = 6371008.8 // Mean radius, used by the equirectangular projection
+)
+
+// scale converts spacing between configured units and degrees around a
+// reference point. perDegree holds the units covered by one degree of
+// longitude and of latitude; it is 1 on both axes when spacing is in degrees.
+type scale struct {
+	perDegree [2]float64
+}
+
+// degreeScale is the identity scale for spacing given in degrees
+var degreeScale = scale{perDegree: [2]float64{1, 1}}
+
+// newScale returns the scale for the delta's unit and projection at the
+// reference position
+func newScale(delta config.CoordinateDelta, reference []float64) scale {
+	if delta.Unit != config.UnitMeters || len(reference) < 2 {
+		return degreeScale
+	}
+
+	if delta.Projection == config.ProjectionUTM {
+		return utmLocalScale(reference[0], reference[1])
+	}
+
+	metersPerDegree := earthRadiusM * math.Pi / 180
+	return scale{perDegree: [2]float64{
+		metersPerDegree * math.Cos(reference[1]*math.Pi/180),
+		metersPerDegree,
+	}}
+}
+
+// lng converts a distance along the parallel to degrees of longitude
+func (s scale) lng(units float64) float64 {
+	return units / s.perDegree[0]
+}
+
+// lat converts a distance along the meridian to degrees of latitude
+func (s scale) lat(units float64) float64 {
+	return units / s.perDegree[1]
+}
+
+// distance returns the length between two positions in configured units
+func (s scale) distance(a, b []float64) float64 {
+	return math.Hypot((b[0]-a[0])*s.perDegree[0], (b[1]-a[1])*s.perDegree[1])
+}
+
+// degreeDelta returns the delta in degrees
+func (s scale) degreeDelta(delta config.CoordinateDelta) config.CoordinateDelta {
+	return config.CoordinateDelta{Longitude: s.lng(delta.Longitude), Latitude: s.lat(delta.Latitude)}
+}
+
+// utmLocalScale linearises the UTM projection of the position's zone around
+// it: the easting and northing covered by a small step in each direction
+func utmLocalScale(lng, lat float64) scale {
+	const step = 1e-4
+	zoneMeridian := utmZoneMeridian(lng)
+
+	e0, n0 := transverseMercator(lng, lat, zoneMeridian)
+	e1, _ := transverseMercator(lng+step, lat, zoneMeridian)
+	_, n1 := transverseMercator(lng, lat+step, zoneMeridian)
+
+	return scale{perDegree: [2]float64{(e1 - e0) / step, (n1 - n0) / step}}
+}
+
+// utmZoneMeridian returns the central meridian of the UTM zone holding lng
+func utmZoneMeridian(lng float64) float64 {
+	zone := math.Floor((lng+180)/6) + 1
+	return (zone-1)*6 - 180 + 3
+}
+
+// transverseMercator projects a position to easting and northing in meters
+// relative to the central meridian, on the WGS84 ellipsoid with the UTM
+// scale factor (Snyder, Map Projections: A Working Manual, eq. 8-9 to 8-10)
+func transverseMercator(lng, lat, centralMeridian float64) (easting, northing float64) {
+	e2 := wgs84F * (2 - wgs84F)
+	ep2 := e2 / (1 - e2)
+
+	phi := lat * math.Pi / 180
+	sinPhi, cosPhi := math.Sin(phi), math.Cos(phi)
+	tanPhi := math.Tan(phi)
+
+	n := wgs84A / math.Sqrt(1-e2*sinPhi*sinPhi)
+	t := tanPhi * tanPhi
+	c := ep2 * cosPhi * cosPhi
+	a := (lng - centralMeridian) * math.Pi / 180 * cosPhi
+
+	m := wgs84A * ((1-e2/4-3*e2*e2/64-5*e2*e2*e2/256)*phi -
+		(3*e2/8+3*e2*e2/32+45*e2*e2*e2/1024)*math.Sin(2*phi) +
+		(15*e2*e2/256+45*e2*e2*e2/1024)*math.Sin(4*phi) -
+		(35*e2*e2*e2/3072)*math.Sin(6*phi))
+
+	easting = utmScale * n * (a + (1-t+c)*math.Pow(a, 3)/6 +
+		(5-18*t+t*t+72*c-58*ep2)*math.Pow(a, 5)/120)
+	northing = utmScale * (m + n*tanPhi*(a*a/2+
+		(5-t+9*c+4*c*c)*math.Pow(a, 4)/24+
+		(61-58*t+t*t+600*c-330*ep2)*math.Pow(a, 6)/720))
+	return easting, northing
+}
+
+// greatCircleDistance returns the haversine distance in meters between two
+// positions on a sphere of the Earth's mean radius
+func greatCircleDistance(a, b []float64) float64 {
+	rad := math.Pi / 180
+	dLat := (b[1] - a[1]) * rad
+	dLng := (b[0] - a[0]) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a[1]*rad)*math.Cos(b[1]*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusM * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,109 @@
+package payload
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"gameday-sim/internal/config"
+)
+
+// applyTiming gives every position of every geometry the time it is reached,
+// encoded as payload.timing.encoding asks. Each line or ring is travelled
+// from the order's departure at a speed drawn from the speed profile.
+func (g *Generator) applyTiming(payloads []OrderPayload) {
+	tc := g.config.Payload.Timing
+	if !tc.Enabled() {
+		return
+	}
+
+	rng := rand.New(rand.NewSource(timingSeed(g.config)))
+	for i := range payloads {
+		p := &payloads[i]
+		if p.Geometry == nil {
+			continue
+		}
+
+		departure := p.Timestamp.Add(tc.Departure.Sample(rng)).Round(time.Millisecond)
+		speed := tc.Speed
+		speed.Cruise *= 1 + speed.Jitter*(2*rng.Float64()-1)
+
+		times := make([][]time.Time, 0, len(p.Geometry.Lines()))
+		for _, line := range p.Geometry.Lines() {
+			times = append(times, lineTimes(line, speed, departure))
+		}
+
+		p.Departure = &departure
+		if tc.Encoding == config.TimingM {
+			p.Geometry.setM(times)
+		} else {
+			p.CoordTimes = times
+		}
+	}
+}
+
+// timingSeed returns the seed for departures and speeds, falling back to a
+// time-based seed when the run is not seeded
+func timingSeed(cfg *config.Config) int64 {
+	if seed := cfg.Simulation.DeriveSeed("timing"); seed != 0 {
+		return seed
+	}
+	return time.Now().UnixNano()
+}
+
+// lineTimes returns the time each position of a line is reached when it is
+// travelled from departure with the speed profile
+func lineTimes(line [][]float64, speed config.SpeedProfile, departure time.Time) []time.Time {
+	along := make([]float64, len(line))
+	for i := 1; i < len(line); i++ {
+		along[i] = along[i-1] + greatCircleDistance(line[i-1], line[i])
+	}
+
+	total := along[len(along)-1]
+	times := make([]time.Time, len(line))
+	for i, s := range along {
+		seconds := travelTime(s, total, speed.Cruise, speed.Acceleration)
+		times[i] = departure.Add(time.Duration(seconds * float64(time.Second))).Round(time.Millisecond)
+	}
+	return times
+}
+
+// travelTime returns the seconds taken to cover distance s of a trip of
+// length total under a trapezoidal speed profile: accelerating from
+// standstill to cruise, cruising, then decelerating to stop at the end.
+// Trips too short to reach cruise speed peak half way. Zero acceleration
+// travels at cruise speed throughout.
+func travelTime(s, total, cruise, acceleration float64) float64 {
+	if acceleration <= 0 {
+		return s / cruise
+	}
+
+	peak := math.Min(cruise, math.Sqrt(acceleration*total))
+	rampLength := peak * peak / (2 * acceleration)
+	rampTime := peak / acceleration
+	tripTime := 2*rampTime + (total-2*rampLength)/peak
+
+	switch {
+	case s <= rampLength:
+		return math.Sqrt(2 * s / acceleration)
+	case s >= total-rampLength:
+		return tripTime - math.Sqrt(2*math.Max(total-s, 0)/acceleration)
+	default:
+		return rampTime + (s-rampLength)/peak
+	}
+}
+
+// setM appends each position's time, in Unix seconds, as its M value. The
+// elevation is kept, or set to 0, so M is always the fourth value.
+func (g *GeoJSONGeometry) setM(times [][]time.Time) {
+	for p, line := range g.Lines() {
+		for i, position := range line {
+			z := 0.0
+			if len(position) > 2 {
+				z = position[2]
+			}
+			m := float64(times[p][i].UnixMilli()) / 1000
+			line[i] = []float64{position[0], position[1], z, m}
+		}
+	}
+}
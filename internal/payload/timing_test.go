@@ -0,0 +1,30 @@
+package payload
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTravelTime(t *testing.T) {
+	tests := []struct {
+		name                    string
+		s, total, cruise, accel float64
+		want                    float64
+	}{
+		{"constant speed", 100, 200, 10, 0, 10},
+		{"accelerating", 8, 200, 10, 1, 4},
+		{"cruising", 100, 200, 10, 1, 15},
+		{"arrival", 200, 200, 10, 1, 30},
+		{"decelerating", 192, 200, 10, 1, 26},
+		{"short trip peaks half way", 50, 100, 20, 1, 10},
+		{"short trip arrival", 100, 100, 20, 1, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := travelTime(tt.s, tt.total, tt.cruise, tt.accel); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("travelTime(%v, %v, %v, %v) = %v, want %v", tt.s, tt.total, tt.cruise, tt.accel, got, tt.want)
+			}
+		})
+	}
+}
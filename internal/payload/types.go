@@ -1,6 +1,11 @@
 package payload
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"gameday-sim/internal/config"
+)
 
 // OrderType represents the type of order flow
 type OrderType string
@@ -12,19 +17,76 @@ const (
 
 // OrderPayload represents the structure of an order
 type OrderPayload struct {
-	OrderNumber  string                 `json:"orderNumber"`
-	Location     string                 `json:"location"`
-	POCOrder     string                 `json:"pocOrder"`
-	Timestamp    time.Time              `json:"timestamp"`
-	Type         OrderType              `json:"type"`
-	CustomFields map[string]interface{} `json:"customFields,omitempty"`
-	Geometry     *GeoJSONGeometry       `json:"geometry,omitempty"`
+	OrderNumber     string                 `json:"orderNumber"`
+	Location        string                 `json:"location"`
+	POCOrder        string                 `json:"pocOrder"`
+	Timestamp       time.Time              `json:"timestamp"`
+	Type            OrderType              `json:"type"`
+	Identity        string                 `json:"identity,omitempty"` // Pool identity the order is placed as
+	CustomFields    map[string]interface{} `json:"customFields,omitempty"`
+	Geometry        *GeoJSONGeometry       `json:"geometry,omitempty"`
+	Departure       *time.Time             `json:"departure,omitempty"`       // When the order sets off along its geometry
+	CoordTimes      [][]time.Time          `json:"coordTimes,omitempty"`      // Time of each position, one list per line or ring
+	Fault           string                 `json:"fault,omitempty"`           // Kind of deliberately bad geometry injected
+	ExpectedOutcome string                 `json:"expectedOutcome,omitempty"` // What the service should do with the fault
 }
 
-// GeoJSONGeometry represents a GeoJSON geometry (LineString)
+// GeoJSONGeometry represents a GeoJSON geometry. Point and LineString
+// positions are held in Coordinates, Polygon rings and MultiLineString
+// lines in Parts.
 type GeoJSONGeometry struct {
-	Type        string      `json:"type"`
-	Coordinates [][]float64 `json:"coordinates"`
+	Type        string
+	Coordinates [][]float64
+	Parts       [][][]float64
+}
+
+// newGeometry builds a geometry of the given type from template parts
+func newGeometry(geometryType string, parts [][][]float64) *GeoJSONGeometry {
+	if geometryType == config.GeometryPolygon || geometryType == config.GeometryMultiLineString {
+		return &GeoJSONGeometry{Type: geometryType, Parts: parts}
+	}
+	return &GeoJSONGeometry{Type: geometryType, Coordinates: parts[0]}
+}
+
+// Lines returns every part of the geometry as a list of positions
+func (g *GeoJSONGeometry) Lines() [][][]float64 {
+	if g.Type == config.GeometryPolygon || g.Type == config.GeometryMultiLineString {
+		return g.Parts
+	}
+	return [][][]float64{g.Coordinates}
+}
+
+// MarshalJSON writes the coordinates nested as the geometry type requires
+func (g GeoJSONGeometry) MarshalJSON() ([]byte, error) {
+	var coordinates interface{} = g.Coordinates
+	switch g.Type {
+	case config.GeometryPoint:
+		if len(g.Coordinates) > 0 {
+			coordinates = g.Coordinates[0]
+		}
+	case config.GeometryPolygon, config.GeometryMultiLineString:
+		coordinates = g.Parts
+	}
+
+	return json.Marshal(struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}{g.Type, coordinates})
+}
+
+// UnmarshalJSON reads any geometry type supported for templates
+func (g *GeoJSONGeometry) UnmarshalJSON(data []byte) error {
+	var raw config.GeoJSONGeometry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parts, err := config.ParseParts(raw)
+	if err != nil {
+		return err
+	}
+	*g = *newGeometry(raw.Type, parts)
+	return nil
 }
 
 // OrderState represents the current state of an order
@@ -0,0 +1,176 @@
+package payload
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gameday-sim/internal/config"
+)
+
+// ErrOverlap is returned by Generate in strict verify mode when generated geometries intersect
+var ErrOverlap = errors.New("generated geometries overlap")
+
+// Overlap is a pair of orders whose geometries intersect
+type Overlap struct {
+	First  string // Order number of the earlier payload
+	Second string
+}
+
+// segment is one edge of a generated geometry. Points are stored as
+// zero-length segments so they take part in the same checks.
+type segment struct {
+	owner int
+	a, b  []float64
+}
+
+// cellKey addresses a cell of the spatial grid
+type cellKey struct{ x, y int }
+
+// spatialGrid buckets segments by the grid cells their bounding boxes cover,
+// so only segments that are close to each other are compared
+type spatialGrid struct {
+	size  float64
+	cells map[cellKey][]int
+}
+
+// VerifyNonOverlap checks every pair of generated geometries for touching or
+// crossing segments, and polygons for containing another geometry. It
+// returns the overlapping pairs ordered by payload index.
+func VerifyNonOverlap(payloads []OrderPayload) []Overlap {
+	var segments []segment
+	for i, p := range payloads {
+		if p.Geometry == nil {
+			continue
+		}
+		for _, line := range p.Geometry.Lines() {
+			if len(line) == 1 {
+				segments = append(segments, segment{owner: i, a: line[0], b: line[0]})
+			}
+			for j := 1; j < len(line); j++ {
+				segments = append(segments, segment{owner: i, a: line[j-1], b: line[j]})
+			}
+		}
+	}
+
+	grid := newSpatialGrid(segments)
+	pairs := make(map[[2]int]bool)
+
+	for i, s := range segments {
+		for _, j := range grid.near(s.a, s.b) {
+			other := segments[j]
+			if j <= i || other.owner == s.owner || pairs[ownerPair(s.owner, other.owner)] {
+				continue
+			}
+			if segmentsIntersect(s.a, s.b, other.a, other.b) {
+				pairs[ownerPair(s.owner, other.owner)] = true
+			}
+		}
+	}
+
+	// A geometry wholly inside a polygon crosses none of its edges
+	for i, p := range payloads {
+		if p.Geometry == nil || p.Geometry.Type != config.GeometryPolygon || len(p.Geometry.Parts) == 0 {
+			continue
+		}
+		exterior := p.Geometry.Parts[0]
+		minX, minY, maxX, maxY := bounds(exterior)
+		for _, j := range grid.near([]float64{minX, minY}, []float64{maxX, maxY}) {
+			other := segments[j]
+			if other.owner == i || pairs[ownerPair(i, other.owner)] {
+				continue
+			}
+			if pointInRing(other.a[0], other.a[1], exterior) {
+				pairs[ownerPair(i, other.owner)] = true
+			}
+		}
+	}
+
+	overlaps := make([]Overlap, 0, len(pairs))
+	keys := make([][2]int, 0, len(pairs))
+	for pair := range pairs {
+		keys = append(keys, pair)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a][0] != keys[b][0] {
+			return keys[a][0] < keys[b][0]
+		}
+		return keys[a][1] < keys[b][1]
+	})
+	for _, pair := range keys {
+		overlaps = append(overlaps, Overlap{First: payloads[pair[0]].OrderNumber, Second: payloads[pair[1]].OrderNumber})
+	}
+	return overlaps
+}
+
+// ownerPair returns the two payload indexes in ascending order
+func ownerPair(a, b int) [2]int {
+	if a > b {
+		return [2]int{b, a}
+	}
+	return [2]int{a, b}
+}
+
+// newSpatialGrid indexes segments on a grid whose cells are about as large
+// as the average segment
+func newSpatialGrid(segments []segment) *spatialGrid {
+	total := 0.0
+	for _, s := range segments {
+		total += math.Max(math.Abs(s.b[0]-s.a[0]), math.Abs(s.b[1]-s.a[1]))
+	}
+
+	size := 1e-6
+	if len(segments) > 0 && total > 0 {
+		size = math.Max(size, total/float64(len(segments)))
+	}
+
+	g := &spatialGrid{size: size, cells: make(map[cellKey][]int)}
+	for i, s := range segments {
+		g.each(s.a, s.b, func(key cellKey) {
+			g.cells[key] = append(g.cells[key], i)
+		})
+	}
+	return g
+}
+
+// each calls fn for every cell covered by the bounding box of a and b
+func (g *spatialGrid) each(a, b []float64, fn func(cellKey)) {
+	x0, x1 := g.cell(math.Min(a[0], b[0])), g.cell(math.Max(a[0], b[0]))
+	y0, y1 := g.cell(math.Min(a[1], b[1])), g.cell(math.Max(a[1], b[1]))
+	for x := x0; x <= x1; x++ {
+		for y := y0; y <= y1; y++ {
+			fn(cellKey{x, y})
+		}
+	}
+}
+
+// cell returns the grid coordinate of v
+func (g *spatialGrid) cell(v float64) int {
+	return int(math.Floor(v / g.size))
+}
+
+// near returns the indexes of segments sharing a cell with the bounding box of a and b
+func (g *spatialGrid) near(a, b []float64) []int {
+	seen := make(map[int]bool)
+	var found []int
+	g.each(a, b, func(key cellKey) {
+		for _, i := range g.cells[key] {
+			if !seen[i] {
+				seen[i] = true
+				found = append(found, i)
+			}
+		}
+	})
+	return found
+}
+
+// bounds returns the bounding box of a list of positions
+func bounds(positions [][]float64) (minX, minY, maxX, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, p := range positions {
+		minX, maxX = math.Min(minX, p[0]), math.Max(maxX, p[0])
+		minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+	}
+	return minX, minY, maxX, maxY
+}
@@ -0,0 +1,36 @@
+package payload
+
+// smoothWeighted picks indexes in proportion to their weights using smooth
+// weighted round-robin, so the sequence is deterministic and each index is
+// spread evenly through it
+type smoothWeighted struct {
+	weights []int
+	current []int
+	total   int
+}
+
+// newSmoothWeighted creates a picker over weights, which must be positive
+func newSmoothWeighted(weights []int) *smoothWeighted {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	return &smoothWeighted{
+		weights: weights,
+		current: make([]int, len(weights)),
+		total:   total,
+	}
+}
+
+// next returns the next index
+func (s *smoothWeighted) next() int {
+	best := 0
+	for i, w := range s.weights {
+		s.current[i] += w
+		if s.current[i] > s.current[best] {
+			best = i
+		}
+	}
+	s.current[best] -= s.total
+	return best
+}
@@ -0,0 +1,116 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/payload"
+	"gameday-sim/internal/simulator"
+)
+
+// SaveOutcomeGeoJSON writes the orders as a FeatureCollection styled by
+// outcome, so that spatial patterns in failures stand out. Each payload is
+// joined with its OrderResult; orders that were never processed are drawn
+// grey. The file goes to dir as outcomes_<stamp>.json and its name is
+// returned.
+func SaveOutcomeGeoJSON(result *simulator.SimulationResult, payloads []payload.OrderPayload, boundaries []config.PolygonBoundary, dir, stamp string) (string, error) {
+	features := []map[string]interface{}{}
+
+	for _, b := range boundaries {
+		name := "Boundary"
+		if b.Name != config.DefaultBoundaryName {
+			name = "Boundary " + b.Name
+		}
+		features = append(features, map[string]interface{}{
+			"type": "Feature",
+			"properties": map[string]interface{}{
+				"name":         name,
+				"stroke":       "#ff0000",
+				"stroke-width": 2,
+				"fill":         "#ff0000",
+				"fill-opacity": 0.1,
+			},
+			"geometry": map[string]interface{}{
+				"type":        config.GeometryMultiPolygon,
+				"coordinates": b.AllPolygons(),
+			},
+		})
+	}
+
+	results := result.ResultsByOrder()
+	for _, pl := range payloads {
+		if pl.Geometry == nil {
+			continue
+		}
+		features = append(features, map[string]interface{}{
+			"type":       "Feature",
+			"properties": outcomeProperties(pl, results),
+			"geometry":   pl.Geometry,
+		})
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"type":     "FeatureCollection",
+		"features": features,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal outcome GeoJSON: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	filename := filepath.Join(dir, fmt.Sprintf("outcomes_%s.json", stamp))
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write outcome GeoJSON: %w", err)
+	}
+	return filename, nil
+}
+
+// outcomeProperties returns the feature properties of an order: what it
+// was generated as, how it ended, and a stroke by final state. Failed
+// orders are drawn wider.
+func outcomeProperties(pl payload.OrderPayload, results map[string]simulator.OrderResult) map[string]interface{} {
+	properties := map[string]interface{}{
+		"orderNumber":  pl.OrderNumber,
+		"type":         string(pl.Type),
+		"stroke":       payload.StateColor(""),
+		"stroke-width": 2,
+	}
+	if pl.Identity != "" {
+		properties["identity"] = pl.Identity
+	}
+	if pl.Fault != "" {
+		properties["fault"] = pl.Fault
+		properties["expectedOutcome"] = pl.ExpectedOutcome
+	}
+
+	r, ok := results[pl.OrderNumber]
+	if !ok {
+		properties["state"] = "not processed"
+		return properties
+	}
+
+	properties["state"] = string(r.State)
+	properties["batchId"] = r.BatchID
+	properties["stroke"] = payload.StateColor(r.State)
+	if r.OrderID != "" {
+		properties["orderId"] = r.OrderID
+	}
+	if r.Outcome != "" {
+		properties["outcome"] = r.Outcome
+	}
+	if r.AcceptanceLatency > 0 {
+		properties["acceptanceLatencyMs"] = r.AcceptanceLatency.Milliseconds()
+	}
+	if r.Error != nil {
+		properties["error"] = r.Error.Error()
+	}
+	if r.State == payload.StateFailed {
+		properties["stroke-width"] = 4
+	}
+	return properties
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,11 +28,128 @@ func PrintResults(result *simulator.SimulationResult, logger *utils.Logger, tota
 	fmt.Printf("Total Batches:      %d\n", len(result.BatchResults))
 	fmt.Printf("Total Duration:     %s\n", totalDuration.Round(time.Millisecond))
 	fmt.Printf("Avg Order Duration: %v\n", stats["avgOrderDuration"])
+	fmt.Printf("Seed:               %d\n", result.Seed)
 	fmt.Println(separator)
 
+	if breakdown := result.IdentityBreakdown(); len(breakdown) > 0 {
+		printIdentities(breakdown)
+	}
+
+	if breakdown := result.FaultBreakdown(); len(breakdown) > 0 {
+		printFaults(breakdown)
+	}
+
+	if result.Metrics != nil {
+		printMetrics(*result.Metrics)
+	}
+
 	logger.Info("Simulation summary", stats)
 }
 
+// printIdentities prints order outcomes per identity
+func printIdentities(breakdown map[string]simulator.IdentityStats) {
+	names := make([]string, 0, len(breakdown))
+	for name := range breakdown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("BY IDENTITY")
+	for _, name := range names {
+		s := breakdown[name]
+		fmt.Printf("  %-16s orders=%d ok=%d failed=%d ended=%d cancelled=%d\n",
+			name, s.Orders, s.Succeeded, s.Failed, s.Ended, s.Cancelled)
+	}
+	fmt.Println(strings.Repeat("=", 80))
+}
+
+// printFaults prints, per injected fault kind, how many orders got the
+// expected outcome and which ones did not
+func printFaults(breakdown map[string]simulator.FaultStats) {
+	kinds := make([]string, 0, len(breakdown))
+	for kind := range breakdown {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	fmt.Println("FAULT INJECTION")
+	for _, kind := range kinds {
+		s := breakdown[kind]
+		fmt.Printf("  %-18s injected=%d matched=%d mismatched=%d\n", kind, s.Injected, s.Matched, len(s.Mismatched))
+	}
+	for _, kind := range kinds {
+		for _, r := range breakdown[kind].Mismatched {
+			outcome := r.Outcome
+			if outcome == "" {
+				outcome = "no outcome"
+			}
+			fmt.Printf("  MISMATCH %s %s: expected %s, got %s\n", r.OrderNumber, kind, r.ExpectedOutcome, outcome)
+		}
+	}
+	fmt.Println(strings.Repeat("=", 80))
+}
+
+// printMetrics prints per-endpoint API metrics and circuit breaker activity
+func printMetrics(snapshot utils.MetricsSnapshot) {
+	if len(snapshot.APICalls) > 0 {
+		endpoints := make([]string, 0, len(snapshot.APICalls))
+		for endpoint := range snapshot.APICalls {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+
+		fmt.Println("API CALLS")
+		for _, endpoint := range endpoints {
+			m := snapshot.APICalls[endpoint]
+			fmt.Printf("  %-10s calls=%d ok=%d failed=%d avg=%s max=%s\n",
+				endpoint, m.TotalCalls, m.SuccessfulCalls, m.FailedCalls,
+				m.AvgDuration.Round(time.Millisecond), m.MaxDuration.Round(time.Millisecond))
+		}
+	}
+
+	if len(snapshot.RateLimitWaits) > 0 {
+		endpoints := make([]string, 0, len(snapshot.RateLimitWaits))
+		for endpoint := range snapshot.RateLimitWaits {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+
+		fmt.Println("RATE LIMITER WAITS")
+		for _, endpoint := range endpoints {
+			w := snapshot.RateLimitWaits[endpoint]
+			fmt.Printf("  %-10s requests=%d total=%s avg=%s max=%s\n",
+				endpoint, w.Requests, w.TotalWait.Round(time.Millisecond),
+				w.AvgWait.Round(time.Millisecond), w.MaxWait.Round(time.Millisecond))
+		}
+	}
+
+	if len(snapshot.Retries) > 0 {
+		endpoints := make([]string, 0, len(snapshot.Retries))
+		for endpoint := range snapshot.Retries {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+
+		fmt.Println("RETRIES")
+		for _, endpoint := range endpoints {
+			fmt.Printf("  %-10s %v\n", endpoint, snapshot.Retries[endpoint])
+		}
+	}
+
+	if len(snapshot.TokenRefreshes) > 0 {
+		fmt.Printf("TOKEN REFRESHES  %v (failed: %d)\n", snapshot.TokenRefreshes, snapshot.TokenRefreshFailures)
+	}
+
+	if len(snapshot.BreakerTransitions) > 0 {
+		fmt.Println("CIRCUIT BREAKER")
+		for _, t := range snapshot.BreakerTransitions {
+			fmt.Printf("  %s  %-10s %s -> %s\n", t.At.Format("15:04:05.000"), t.Endpoint, t.From, t.To)
+		}
+	}
+
+	fmt.Println(strings.Repeat("=", 80))
+}
+
 // SaveResultsToJSON saves simulation results to a JSON file
 func SaveResultsToJSON(result *simulator.SimulationResult, filename string) error {
 	data, err := json.MarshalIndent(result, "", "  ")
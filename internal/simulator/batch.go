@@ -19,6 +19,7 @@ type BatchProcessor struct {
 	orderProcessor  *OrderProcessor
 	terminationChan chan TerminationRequest
 	opsTracker      *utils.OperationsTracker
+	workerDone      chan struct{} // Closed when the termination worker stops
 }
 
 // NewBatchProcessor creates a new batch processor
@@ -36,7 +37,32 @@ func NewBatchProcessor(apiClient *api.Client, cfg *config.Config, opsTracker *ut
 
 // StartTerminationWorker starts the background worker for processing terminations
 func (bp *BatchProcessor) StartTerminationWorker(ctx context.Context) {
-	go TerminationWorker(ctx, bp.apiClient, bp.terminationChan)
+	bp.workerDone = make(chan struct{})
+	go func() {
+		defer close(bp.workerDone)
+		TerminationWorker(ctx, bp.apiClient, bp.terminationChan)
+	}()
+}
+
+// awaitTerminations blocks until every scheduled termination has been
+// processed, or until ctx is done and the worker has stopped, so that no
+// order result is still being written when the results are read
+func (bp *BatchProcessor) awaitTerminations(ctx context.Context) {
+	if bp.workerDone == nil {
+		return
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		bp.orderProcessor.pending.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		<-bp.workerDone
+	}
 }
 
 // Close closes the termination channel
@@ -99,6 +125,9 @@ func (bp *BatchProcessor) ProcessBatches(ctx context.Context, batches []payload.
 		result.FailedOrders += batchResult.FailedOrders
 	}
 
+	// Final states are written by the termination worker
+	bp.awaitTerminations(ctx)
+
 	// Check for errors
 	for err := range errorsChan {
 		if err != nil {
@@ -118,8 +147,9 @@ func (bp *BatchProcessor) processSingleBatch(ctx context.Context, batch payload.
 		BatchID:      batch.ID,
 		StartTime:    time.Now(),
 		TotalOrders:  len(batch.Payloads),
-		OrderResults: make([]OrderResult, 0, len(batch.Payloads)),
+		OrderResults: make([]*OrderResult, 0, len(batch.Payloads)),
 	}
+	processor := bp.orderProcessor.forBatch(batch.ID)
 
 	// Process each payload in the batch sequentially
 	for i, pl := range batch.Payloads {
@@ -130,21 +160,19 @@ func (bp *BatchProcessor) processSingleBatch(ctx context.Context, batch payload.
 		}
 
 		// Process the order
-		orderResult, err := bp.orderProcessor.ProcessOrder(ctx, pl)
+		orderResult, err := processor.ProcessOrder(ctx, pl)
 		if err != nil {
 			result.FailedOrders++
 		} else {
 			result.SuccessfulOrders++
 		}
 
-		result.OrderResults = append(result.OrderResults, *orderResult)
+		result.OrderResults = append(result.OrderResults, orderResult)
 
 		// Wait between creates (except for last item)
 		if i < len(batch.Payloads)-1 {
-			select {
-			case <-ctx.Done():
+			if err := processor.wait(ctx, bp.config.Intervals.BetweenCreates); err != nil {
 				return result
-			case <-time.After(bp.config.Intervals.BetweenCreates):
 			}
 		}
 	}
@@ -161,7 +189,7 @@ type BatchResult struct {
 	TotalOrders      int
 	SuccessfulOrders int
 	FailedOrders     int
-	OrderResults     []OrderResult
+	OrderResults     []*OrderResult // Shared with the termination worker until ProcessBatches returns
 	StartTime        time.Time
 	EndTime          time.Time
 	Duration         time.Duration
@@ -169,6 +197,7 @@ type BatchResult struct {
 
 // SimulationResult represents the overall simulation result
 type SimulationResult struct {
+	Seed             int64 // Simulation seed; rerun with --seed to replay
 	TotalOrders      int
 	SuccessfulOrders int
 	FailedOrders     int
@@ -176,6 +205,102 @@ type SimulationResult struct {
 	StartTime        time.Time
 	EndTime          time.Time
 	Duration         time.Duration
+	Metrics          *utils.MetricsSnapshot `json:",omitempty"`
+	Config           *config.Config         `json:",omitempty"` // Effective configuration with credentials redacted
+}
+
+// IdentityStats summarizes the orders placed by a single identity
+type IdentityStats struct {
+	Orders    int
+	Succeeded int
+	Failed    int
+	Ended     int
+	Cancelled int
+}
+
+// IdentityBreakdown groups order outcomes by identity. It returns nil when
+// the run did not use an identity pool.
+func (sr *SimulationResult) IdentityBreakdown() map[string]IdentityStats {
+	var breakdown map[string]IdentityStats
+	for _, batchResult := range sr.BatchResults {
+		for _, orderResult := range batchResult.OrderResults {
+			if orderResult.Identity == "" {
+				continue
+			}
+			if breakdown == nil {
+				breakdown = make(map[string]IdentityStats)
+			}
+
+			stats := breakdown[orderResult.Identity]
+			stats.Orders++
+			if orderResult.Error != nil || orderResult.State == payload.StateFailed {
+				stats.Failed++
+			} else {
+				stats.Succeeded++
+			}
+			switch orderResult.State {
+			case payload.StateEnded:
+				stats.Ended++
+			case payload.StateCancelled:
+				stats.Cancelled++
+			}
+			breakdown[orderResult.Identity] = stats
+		}
+	}
+	return breakdown
+}
+
+// FaultStats summarizes the orders of one injected fault kind
+type FaultStats struct {
+	Injected   int
+	Matched    int           // Orders with the expected outcome
+	Mismatched []OrderResult // Orders the service reacted to differently
+}
+
+// FaultBreakdown groups injected faults by kind. It returns nil when the run
+// injected no faults.
+func (sr *SimulationResult) FaultBreakdown() map[string]FaultStats {
+	var breakdown map[string]FaultStats
+	for _, batchResult := range sr.BatchResults {
+		for _, orderResult := range batchResult.OrderResults {
+			if orderResult.Fault == "" {
+				continue
+			}
+			if breakdown == nil {
+				breakdown = make(map[string]FaultStats)
+			}
+
+			stats := breakdown[orderResult.Fault]
+			stats.Injected++
+			if orderResult.FaultMatched() {
+				stats.Matched++
+			} else {
+				stats.Mismatched = append(stats.Mismatched, *orderResult)
+			}
+			breakdown[orderResult.Fault] = stats
+		}
+	}
+	return breakdown
+}
+
+// FinalStates returns the last known state of every order by order number
+func (sr *SimulationResult) FinalStates() map[string]payload.OrderState {
+	states := make(map[string]payload.OrderState)
+	for orderNumber, orderResult := range sr.ResultsByOrder() {
+		states[orderNumber] = orderResult.State
+	}
+	return states
+}
+
+// ResultsByOrder returns the result of every processed order by order number
+func (sr *SimulationResult) ResultsByOrder() map[string]OrderResult {
+	results := make(map[string]OrderResult)
+	for _, batchResult := range sr.BatchResults {
+		for _, orderResult := range batchResult.OrderResults {
+			results[orderResult.OrderNumber] = *orderResult
+		}
+	}
+	return results
 }
 
 // GetStats returns statistics about the simulation
@@ -229,5 +354,6 @@ func (sr *SimulationResult) GetStats() map[string]interface{} {
 		"totalDuration":     sr.Duration.String(),
 		"avgOrderDuration":  avgDuration.String(),
 		"totalBatches":      len(sr.BatchResults),
+		"seed":              sr.Seed,
 	}
 }
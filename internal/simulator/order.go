@@ -2,7 +2,9 @@ package simulator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"gameday-sim/internal/api"
@@ -16,6 +18,8 @@ type TerminationRequest struct {
 	OrderID string
 	Action  TerminationAction
 	Result  *OrderResult
+
+	done func() // Called once the termination has been processed
 }
 
 // TerminationAction defines the type of termination
@@ -32,6 +36,9 @@ type OrderProcessor struct {
 	config          *config.Config
 	terminationChan chan<- TerminationRequest
 	opsTracker      *utils.OperationsTracker
+	jitter          *utils.LockedRand
+	batchID         int             // Batch the processor works on; set by forBatch
+	pending         *sync.WaitGroup // Terminations handed off and not yet processed
 }
 
 // NewOrderProcessor creates a new order processor
@@ -41,31 +48,76 @@ func NewOrderProcessor(apiClient *api.Client, cfg *config.Config, terminationCha
 		config:          cfg,
 		terminationChan: terminationChan,
 		opsTracker:      opsTracker,
+		jitter:          utils.NewLockedRand(jitterSeed(cfg)),
+		pending:         &sync.WaitGroup{},
+	}
+}
+
+// jitterSeed prefers an explicit interval seed over the derived simulation seed
+func jitterSeed(cfg *config.Config) int64 {
+	if cfg.Intervals.Seed != 0 {
+		return cfg.Intervals.Seed
+	}
+	return cfg.Simulation.DeriveSeed("jitter")
+}
+
+// forBatch returns a processor drawing jitter from its own stream, seeded
+// from the jitter seed plus the batch ID, so that parallel batches do not
+// share a sequence whose order depends on goroutine scheduling
+func (p *OrderProcessor) forBatch(batchID int) *OrderProcessor {
+	batch := *p
+	batch.batchID = batchID
+	seed := jitterSeed(p.config)
+	if seed != 0 {
+		seed += int64(batchID)
+	}
+	batch.jitter = utils.NewLockedRand(seed)
+	return &batch
+}
+
+// wait blocks for a duration sampled from the interval, or until ctx is done
+func (p *OrderProcessor) wait(ctx context.Context, interval config.Interval) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(interval.Sample(p.jitter)):
+		return nil
 	}
 }
 
 // ProcessOrder executes the full lifecycle for an order based on its type
 func (p *OrderProcessor) ProcessOrder(ctx context.Context, pl payload.OrderPayload) (*OrderResult, error) {
 	result := &OrderResult{
-		OrderNumber: pl.OrderNumber,
-		Type:        pl.Type,
-		StartTime:   time.Now(),
+		OrderNumber:     pl.OrderNumber,
+		BatchID:         p.batchID,
+		Identity:        pl.Identity,
+		Type:            pl.Type,
+		StartTime:       time.Now(),
+		Fault:           pl.Fault,
+		ExpectedOutcome: pl.ExpectedOutcome,
 	}
 
+	// Every API call for this order authenticates as its identity
+	ctx = api.WithIdentity(ctx, pl.Identity)
+
 	// Step 1: Create the order
 	createResp, err := p.createOrder(ctx, pl)
 	if err != nil {
 		result.Error = err
 		result.State = payload.StateFailed
+		if api.IsClientError(err) {
+			result.Outcome = config.OutcomeRejected
+		}
 		return result, err
 	}
 
 	result.OrderID = createResp.OrderID
 	result.State = payload.StateCreated
+	createdAt := time.Now()
 
 	// Track the order ID for cleanup purposes
 	if p.opsTracker != nil {
-		if err := p.opsTracker.TrackOrder(createResp.OrderID); err != nil {
+		if err := p.opsTracker.TrackOrder(createResp.OrderID, pl.Identity); err != nil {
 			// Log error but don't fail the order creation
 			fmt.Printf("Warning: failed to track order ID %s: %v\n", createResp.OrderID, err)
 		}
@@ -75,12 +127,18 @@ func (p *OrderProcessor) ProcessOrder(ctx context.Context, pl payload.OrderPaylo
 	if err := p.waitForAcceptance(ctx, createResp.OrderID); err != nil {
 		result.Error = err
 		result.State = payload.StateFailed
+		if errors.Is(err, ErrOrderFailed) {
+			result.Outcome = config.OutcomeFailed
+		}
 		return result, err
 	}
 
 	result.State = payload.StateAccepted
+	result.Outcome = config.OutcomeAccepted
+	result.AcceptanceLatency = time.Since(createdAt)
 
-	// Step 3: Execute type-specific flow
+	// Step 3: Execute type-specific flow. Once a flow schedules the
+	// termination, result belongs to the termination worker.
 	if pl.Type == payload.TypeActivate {
 		if err := p.activateFlow(ctx, createResp.OrderID, result); err != nil {
 			result.Error = err
@@ -93,12 +151,12 @@ func (p *OrderProcessor) ProcessOrder(ctx context.Context, pl payload.OrderPaylo
 		}
 	}
 
-	result.EndTime = time.Now()
-	result.Duration = result.EndTime.Sub(result.StartTime)
-
 	return result, nil
 }
 
+// ErrOrderFailed is returned when the service reports an order failed before accepting it
+var ErrOrderFailed = errors.New("order failed during processing")
+
 // createOrder creates a new order via API
 func (p *OrderProcessor) createOrder(ctx context.Context, pl payload.OrderPayload) (*api.CreateOrderResponse, error) {
 	resp, err := p.apiClient.CreateOrder(ctx, pl)
@@ -111,16 +169,12 @@ func (p *OrderProcessor) createOrder(ctx context.Context, pl payload.OrderPayloa
 // waitForAcceptance polls the details API until order is accepted
 func (p *OrderProcessor) waitForAcceptance(ctx context.Context, orderID string) error {
 	// Wait initial interval after creation
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(p.config.Intervals.AfterCreateBeforeGet):
+	if err := p.wait(ctx, p.config.Intervals.AfterCreateBeforeGet); err != nil {
+		return err
 	}
 
-	// Poll until accepted with timeout
+	// Poll until accepted with timeout; each poll interval is sampled separately
 	timeout := time.After(p.config.Cleanup.CancelTimeout)
-	ticker := time.NewTicker(p.config.Intervals.BetweenGetPolls)
-	defer ticker.Stop()
 
 	for {
 		select {
@@ -128,18 +182,22 @@ func (p *OrderProcessor) waitForAcceptance(ctx context.Context, orderID string)
 			return ctx.Err()
 		case <-timeout:
 			return fmt.Errorf("timeout waiting for order acceptance")
-		case <-ticker.C:
+		case <-time.After(p.config.Intervals.BetweenGetPolls.Sample(p.jitter)):
 			resp, err := p.apiClient.GetDetails(ctx, orderID)
 			if err != nil {
 				return fmt.Errorf("failed to get order details: %w", err)
 			}
 
-			if resp.Status == "Accepted" {
+			status := p.apiClient.ResolveStatus(resp.Status)
+			switch {
+			case status.Failed:
+				return fmt.Errorf("%w (status %q)", ErrOrderFailed, resp.Status)
+			case status.IsAccepted():
 				return nil
-			}
-
-			if resp.Status == "Failed" {
-				return fmt.Errorf("order failed during processing")
+			case status.Terminal:
+				return fmt.Errorf("order reached terminal status %q before acceptance", resp.Status)
+			case !status.Retryable:
+				return fmt.Errorf("unexpected order status %q while waiting for acceptance", resp.Status)
 			}
 		}
 	}
@@ -148,10 +206,8 @@ func (p *OrderProcessor) waitForAcceptance(ctx context.Context, orderID string)
 // activateFlow handles the activation flow: activate -> schedule end
 func (p *OrderProcessor) activateFlow(ctx context.Context, orderID string, result *OrderResult) error {
 	// Wait before activation
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(p.config.Intervals.BeforeActivate):
+	if err := p.wait(ctx, p.config.Intervals.BeforeActivate); err != nil {
+		return err
 	}
 
 	// Activate the order
@@ -164,53 +220,65 @@ func (p *OrderProcessor) activateFlow(ctx context.Context, orderID string, resul
 	result.State = payload.StateActivated
 
 	// Wait before scheduling termination
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(p.config.Intervals.BeforeEnd):
+	if err := p.wait(ctx, p.config.Intervals.BeforeEnd); err != nil {
+		return err
 	}
 
-	// Push to termination channel for async processing
-	p.terminationChan <- TerminationRequest{
-		OrderID: orderID,
-		Action:  ActionEnd,
-		Result:  result,
-	}
-
-	result.State = payload.StatePendingEnd
+	p.scheduleTermination(orderID, ActionEnd, payload.StatePendingEnd, result)
 	return nil
 }
 
 // acceptedFlow handles the accepted-only flow: schedule cancel
 func (p *OrderProcessor) acceptedFlow(ctx context.Context, orderID string, result *OrderResult) error {
 	// Wait before scheduling cancellation
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(p.config.Intervals.BeforeCancel):
-	}
-
-	// Push to termination channel for async processing
-	p.terminationChan <- TerminationRequest{
-		OrderID: orderID,
-		Action:  ActionCancel,
-		Result:  result,
+	if err := p.wait(ctx, p.config.Intervals.BeforeCancel); err != nil {
+		return err
 	}
 
-	result.State = payload.StatePendingCancel
+	p.scheduleTermination(orderID, ActionCancel, payload.StatePendingCancel, result)
 	return nil
 }
 
+// scheduleTermination marks the order pending and pushes it to the
+// termination channel for async processing. The worker writes the final
+// state through result, so nothing may touch it after the push.
+func (p *OrderProcessor) scheduleTermination(orderID string, action TerminationAction, state payload.OrderState, result *OrderResult) {
+	result.State = state
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	req := TerminationRequest{OrderID: orderID, Action: action, Result: result}
+	if p.pending != nil {
+		p.pending.Add(1)
+		req.done = p.pending.Done
+	}
+	p.terminationChan <- req
+}
+
 // OrderResult represents the result of processing an order
 type OrderResult struct {
 	OrderNumber string
 	OrderID     string
+	BatchID     int
+	Identity    string
 	Type        payload.OrderType
 	State       payload.OrderState
 	StartTime   time.Time
 	EndTime     time.Time
 	Duration    time.Duration
 	Error       error
+
+	AcceptanceLatency time.Duration // From the create response to the poll that saw the order accepted
+
+	Fault           string // Injected fault kind, empty for regular orders
+	ExpectedOutcome string // Outcome expected for the fault
+	Outcome         string // rejected, failed or accepted; empty when the order never got that far
+}
+
+// FaultMatched reports whether an order with an injected fault got the
+// outcome it was expected to
+func (r OrderResult) FaultMatched() bool {
+	return r.Outcome == r.ExpectedOutcome
 }
 
 // TerminationWorker processes termination requests from the channel
@@ -221,12 +289,17 @@ func TerminationWorker(ctx context.Context, apiClient *api.Client, terminationCh
 			return
 		case req := <-terminationChan:
 			processTermination(ctx, apiClient, req)
+			if req.done != nil {
+				req.done()
+			}
 		}
 	}
 }
 
 // processTermination handles the actual termination API call
 func processTermination(ctx context.Context, apiClient *api.Client, req TerminationRequest) {
+	ctx = api.WithIdentity(ctx, req.Result.Identity)
+
 	switch req.Action {
 	case ActionEnd:
 		_, err := apiClient.EndOrder(ctx, req.OrderID)
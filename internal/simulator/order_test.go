@@ -2,6 +2,7 @@ package simulator
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -28,12 +29,12 @@ func createTestConfig() *config.Config {
 			OrderNumberPrefix: "ORD-TEST-",
 		},
 		Intervals: config.IntervalConfig{
-			BetweenCreates:       10 * time.Millisecond,
-			AfterCreateBeforeGet: 10 * time.Millisecond,
-			BetweenGetPolls:      10 * time.Millisecond,
-			BeforeActivate:       10 * time.Millisecond,
-			BeforeCancel:         10 * time.Millisecond,
-			BeforeEnd:            10 * time.Millisecond,
+			BetweenCreates:       config.FixedInterval(10 * time.Millisecond),
+			AfterCreateBeforeGet: config.FixedInterval(10 * time.Millisecond),
+			BetweenGetPolls:      config.FixedInterval(10 * time.Millisecond),
+			BeforeActivate:       config.FixedInterval(10 * time.Millisecond),
+			BeforeCancel:         config.FixedInterval(10 * time.Millisecond),
+			BeforeEnd:            config.FixedInterval(10 * time.Millisecond),
 		},
 		API: config.APIConfig{
 			BaseURL:      "http://localhost:8080",
@@ -576,3 +577,238 @@ func TestMultipleTerminationRequests(t *testing.T) {
 		}
 	}
 }
+
+// TestWaitForAcceptance_StatusMapping tests that configured statuses drive the acceptance poll
+func TestWaitForAcceptance_StatusMapping(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []string
+		shouldError bool
+	}{
+		{name: "custom casing accepted", statuses: []string{"PENDING", "ACCEPTED"}, shouldError: false},
+		{name: "rejected is failed", statuses: []string{"pending", "Rejected"}, shouldError: true},
+		{name: "ended is terminal", statuses: []string{"Ended"}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := 0
+			server := createMockServer(t, map[string]http.HandlerFunc{
+				"/details": func(w http.ResponseWriter, r *http.Request) {
+					mu.Lock()
+					status := tt.statuses[calls]
+					if calls < len(tt.statuses)-1 {
+						calls++
+					}
+					mu.Unlock()
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"orderId": "order-123", "status": "` + status + `"}`))
+				},
+			})
+			defer server.Close()
+
+			cfg := createTestConfig()
+			cfg.API.BaseURL = server.URL
+			cfg.Statuses = config.StatusConfig{
+				"Rejected": {State: "failed", Terminal: true, Failed: true},
+				"Ended":    {State: "ended", Terminal: true},
+			}
+			client := api.NewClient(cfg, nil) // No auth needed for tests
+			processor := NewOrderProcessor(client, cfg, make(chan TerminationRequest, 1), nil)
+
+			err := processor.waitForAcceptance(context.Background(), "order-123")
+			if tt.shouldError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+// TestProcessOrder_FaultOutcome tests that the service's reaction to an order is recorded as its outcome
+func TestProcessOrder_FaultOutcome(t *testing.T) {
+	tests := []struct {
+		name         string
+		createStatus int
+		status       string
+		expected     string
+	}{
+		{name: "rejected on create", createStatus: http.StatusBadRequest, expected: config.OutcomeRejected},
+		{name: "failed while processing", createStatus: http.StatusOK, status: "failed", expected: config.OutcomeFailed},
+		{name: "accepted", createStatus: http.StatusOK, status: "accepted", expected: config.OutcomeAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createMockServer(t, map[string]http.HandlerFunc{
+				"/operation/payload": func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]interface{}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["geometry"] == nil {
+						t.Errorf("expected the create request to carry the geometry, got %v (%v)", body, err)
+					}
+					w.WriteHeader(tt.createStatus)
+					w.Write([]byte(`{"orderId": "order-123", "status": "pending", "message": "degenerate geometry"}`))
+				},
+				"/details": func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"orderId": "order-123", "status": "` + tt.status + `"}`))
+				},
+			})
+			defer server.Close()
+
+			cfg := createTestConfig()
+			cfg.API.BaseURL = server.URL
+			client := api.NewClient(cfg, nil) // No auth needed for tests
+			processor := NewOrderProcessor(client, cfg, make(chan TerminationRequest, 1), nil)
+
+			pl := createTestPayload(payload.TypeAccepted)
+			pl.Fault = config.FaultDegenerate
+			pl.ExpectedOutcome = config.OutcomeRejected
+
+			result, _ := processor.ProcessOrder(context.Background(), pl)
+			if result.Outcome != tt.expected {
+				t.Errorf("Outcome = %q, expected %q", result.Outcome, tt.expected)
+			}
+			if result.FaultMatched() != (tt.expected == config.OutcomeRejected) {
+				t.Errorf("FaultMatched = %v for outcome %q", result.FaultMatched(), result.Outcome)
+			}
+		})
+	}
+}
+
+func TestForBatch_JitterStreams(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Simulation.Seed = 42
+	processor := NewOrderProcessor(api.NewClient(cfg, nil), cfg, nil, nil)
+
+	draw := func(p *OrderProcessor) []float64 {
+		values := make([]float64, 5)
+		for i := range values {
+			values[i] = p.jitter.Float64()
+		}
+		return values
+	}
+
+	// Draws from one batch do not shift another batch's stream
+	first := draw(processor.forBatch(1))
+	draw(processor.forBatch(2))
+	again := draw(processor.forBatch(1))
+	other := draw(processor.forBatch(2))
+
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatalf("batch 1 stream not reproducible: %v vs %v", first, again)
+		}
+	}
+	if first[0] == other[0] && first[1] == other[1] {
+		t.Errorf("batches 1 and 2 share a stream: %v", first)
+	}
+	if processor.forBatch(1).jitter == processor.jitter {
+		t.Error("forBatch should not share the processor's jitter source")
+	}
+}
+
+func TestProcessBatches_WaitsForTerminations(t *testing.T) {
+	terminate := func(status string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			// Terminations land after the batches themselves are done
+			time.Sleep(50 * time.Millisecond)
+			w.Write([]byte(`{"orderId": "order-123", "status": "` + status + `"}`))
+		}
+	}
+	server := createMockServer(t, map[string]http.HandlerFunc{
+		"/operation/payload": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"orderId": "order-123", "status": "pending"}`))
+		},
+		"/details": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"orderId": "order-123", "status": "accepted"}`))
+		},
+		"/activate": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"orderId": "order-123", "status": "activated"}`))
+		},
+		"/end":    terminate("ended"),
+		"/cancel": terminate("cancelled"),
+	})
+	defer server.Close()
+
+	cfg := createTestConfig()
+	cfg.API.BaseURL = server.URL
+	bp := NewBatchProcessor(api.NewClient(cfg, nil), cfg, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	bp.StartTerminationWorker(ctx)
+
+	newPayload := func(orderNumber string, orderType payload.OrderType) payload.OrderPayload {
+		pl := createTestPayload(orderType)
+		pl.OrderNumber = orderNumber
+		return pl
+	}
+	batches := []payload.Batch{
+		{ID: 0, Payloads: []payload.OrderPayload{newPayload("ORD-1", payload.TypeActivate), newPayload("ORD-2", payload.TypeAccepted)}},
+		{ID: 1, Payloads: []payload.OrderPayload{newPayload("ORD-3", payload.TypeAccepted)}},
+	}
+
+	result, err := bp.ProcessBatches(ctx, batches)
+	if err != nil {
+		t.Fatalf("ProcessBatches: %v", err)
+	}
+
+	want := map[string]struct {
+		state   payload.OrderState
+		batchID int
+	}{
+		"ORD-1": {payload.StateEnded, 0},
+		"ORD-2": {payload.StateCancelled, 0},
+		"ORD-3": {payload.StateCancelled, 1},
+	}
+	results := result.ResultsByOrder()
+	for orderNumber, w := range want {
+		r := results[orderNumber]
+		if r.State != w.state || r.BatchID != w.batchID {
+			t.Errorf("%s: state %s in batch %d, expected %s in batch %d", orderNumber, r.State, r.BatchID, w.state, w.batchID)
+		}
+		if r.AcceptanceLatency <= 0 {
+			t.Errorf("%s: expected an acceptance latency", orderNumber)
+		}
+	}
+}
+
+func TestProcessOrder_SendsTiming(t *testing.T) {
+	departure := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	server := createMockServer(t, map[string]http.HandlerFunc{
+		"/operation/payload": func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Departure  *time.Time    `json:"departure"`
+				CoordTimes [][]time.Time `json:"coordTimes"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("failed to decode the create request: %v", err)
+			}
+			if body.Departure == nil || !body.Departure.Equal(departure) {
+				t.Errorf("departure = %v, expected %v", body.Departure, departure)
+			}
+			if len(body.CoordTimes) != 1 || len(body.CoordTimes[0]) != 2 || !body.CoordTimes[0][1].Equal(departure.Add(time.Minute)) {
+				t.Errorf("unexpected coordTimes %v", body.CoordTimes)
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "stop here"}`))
+		},
+	})
+	defer server.Close()
+
+	cfg := createTestConfig()
+	cfg.API.BaseURL = server.URL
+	client := api.NewClient(cfg, nil) // No auth needed for tests
+	processor := NewOrderProcessor(client, cfg, make(chan TerminationRequest, 1), nil)
+
+	pl := createTestPayload(payload.TypeAccepted)
+	pl.Departure = &departure
+	pl.CoordTimes = [][]time.Time{{departure, departure.Add(time.Minute)}}
+
+	processor.ProcessOrder(context.Background(), pl)
+}
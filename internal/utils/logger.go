@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,22 +21,53 @@ const (
 	ERROR LogLevel = "ERROR"
 )
 
+// redactedValue replaces secrets in log output
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeys are the endings of field names whose values are always
+// redacted, compared without case, "_" or "-". Matching the ending catches
+// access_token or clientSecret but leaves tokenUrl or tokens_per_second alone.
+var sensitiveKeys = []string{"password", "secret", "token", "apikey", "authorization"}
+
+// sensitiveKey reports whether values of the field are credentials
+func sensitiveKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.HasSuffix(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
 // Logger provides structured logging using slog
 type Logger struct {
-	slog    *slog.Logger
-	logFile *os.File
+	slog     *slog.Logger
+	logFile  *os.File
+	redactor *redactor
+}
+
+// redactor masks registered secret values and sensitive fields in log records
+type redactor struct {
+	mu      sync.RWMutex
+	secrets []string
 }
 
 // NewLogger creates a new logger with dual output (console + file)
 func NewLogger(level LogLevel) *Logger {
+	r := &redactor{}
+	options := &slog.HandlerOptions{
+		Level:       toSlogLevel(level),
+		ReplaceAttr: r.replaceAttr,
+	}
+
 	// Create log file with date/timestamp structure
 	logFile, err := createLogFile()
 	if err != nil {
 		slog.Warn("Failed to create log file, logging to console only", "error", err)
 		return &Logger{
-			slog: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-				Level: toSlogLevel(level),
-			})),
+			slog:     slog.New(slog.NewJSONHandler(os.Stdout, options)),
+			redactor: r,
 		}
 	}
 
@@ -42,14 +75,49 @@ func NewLogger(level LogLevel) *Logger {
 	multiWriter := io.MultiWriter(os.Stdout, logFile)
 
 	// Create JSON handler with the specified level
-	handler := slog.NewJSONHandler(multiWriter, &slog.HandlerOptions{
-		Level: toSlogLevel(level),
-	})
+	handler := slog.NewJSONHandler(multiWriter, options)
 
 	return &Logger{
-		slog:    slog.New(handler),
-		logFile: logFile,
+		slog:     slog.New(handler),
+		logFile:  logFile,
+		redactor: r,
+	}
+}
+
+// RegisterSecrets adds values that must never appear in log output
+func (l *Logger) RegisterSecrets(secrets ...string) {
+	l.redactor.mu.Lock()
+	defer l.redactor.mu.Unlock()
+
+	for _, s := range secrets {
+		if s != "" {
+			l.redactor.secrets = append(l.redactor.secrets, s)
+		}
+	}
+}
+
+// replaceAttr redacts sensitive fields and any registered secret inside string values
+func (r *redactor) replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKey(a.Key) {
+		return slog.String(a.Key, redactedValue)
+	}
+
+	if a.Value.Kind() != slog.KindString && a.Value.Kind() != slog.KindAny {
+		return a
+	}
+
+	value := a.Value.String()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	redacted := value
+	for _, s := range r.secrets {
+		redacted = strings.ReplaceAll(redacted, s, redactedValue)
+	}
+	if redacted != value {
+		return slog.String(a.Key, redacted)
 	}
+	return a
 }
 
 // toSlogLevel converts our LogLevel to slog.Level
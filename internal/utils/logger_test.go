@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestRedactorReplaceAttr(t *testing.T) {
+	r := &redactor{secrets: []string{"s3cr3t"}}
+
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"token", "abc", redactedValue},
+		{"access_token", "abc", redactedValue},
+		{"refreshToken", "abc", redactedValue},
+		{"client_secret", "abc", redactedValue},
+		{"Password", "abc", redactedValue},
+		{"X-Api-Key", "abc", redactedValue},
+		{"Authorization", "Bearer abc", redactedValue},
+		{"tokenUrl", "https://auth.example.com/token", "https://auth.example.com/token"},
+		{"token_url", "https://auth.example.com/token", "https://auth.example.com/token"},
+		{"tokens_per_second", "12", "12"},
+		{"error", "request with s3cr3t failed", "request with [REDACTED] failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got := r.replaceAttr(nil, slog.String(tt.key, tt.value))
+			if got.Value.String() != tt.want {
+				t.Errorf("replaceAttr(%s) = %q, want %q", tt.key, got.Value.String(), tt.want)
+			}
+		})
+	}
+}
@@ -10,18 +10,31 @@ type Metrics struct {
 	mu sync.RWMutex
 
 	// API call metrics
-	apiCalls       map[string]int
-	apiSuccesses   map[string]int
-	apiFailures    map[string]int
-	apiDurations   map[string][]time.Duration
+	apiCalls     map[string]int
+	apiSuccesses map[string]int
+	apiFailures  map[string]int
+	apiDurations map[string][]time.Duration
 
 	// Order state metrics
-	orderStates    map[string]int
+	orderStates map[string]int
 
 	// Batch metrics
 	batchesStarted   int
 	batchesCompleted int
 	batchesFailed    int
+
+	// Circuit breaker metrics
+	breakerTransitions []BreakerTransition
+
+	// Client-side rate limiter waits
+	rateLimitWaits map[string][]time.Duration
+
+	// Retries per endpoint, keyed by reason
+	retries map[string]map[string]int
+
+	// Token refreshes by trigger
+	tokenRefreshes       map[string]int
+	tokenRefreshFailures int
 }
 
 // NewMetrics creates a new metrics tracker
@@ -32,6 +45,10 @@ func NewMetrics() *Metrics {
 		apiFailures:  make(map[string]int),
 		apiDurations: make(map[string][]time.Duration),
 		orderStates:  make(map[string]int),
+
+		rateLimitWaits: make(map[string][]time.Duration),
+		retries:        make(map[string]map[string]int),
+		tokenRefreshes: make(map[string]int),
 	}
 }
 
@@ -78,17 +95,65 @@ func (m *Metrics) RecordBatchCompleted(success bool) {
 	}
 }
 
+// RecordBreakerTransition records a circuit breaker state change
+func (m *Metrics) RecordBreakerTransition(endpoint, from, to string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.breakerTransitions = append(m.breakerTransitions, BreakerTransition{
+		Endpoint: endpoint,
+		From:     from,
+		To:       to,
+		At:       time.Now(),
+	})
+}
+
+// RecordRateLimitWait records time spent waiting on an endpoint's rate limiter
+func (m *Metrics) RecordRateLimitWait(endpoint string, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rateLimitWaits[endpoint] = append(m.rateLimitWaits[endpoint], wait)
+}
+
+// RecordRetry records a retried request and why it was retried
+func (m *Metrics) RecordRetry(endpoint, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.retries[endpoint] == nil {
+		m.retries[endpoint] = make(map[string]int)
+	}
+	m.retries[endpoint][reason]++
+}
+
+// RecordTokenRefresh records an auth token refresh and what triggered it
+func (m *Metrics) RecordTokenRefresh(trigger string, success bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokenRefreshes[trigger]++
+	if !success {
+		m.tokenRefreshFailures++
+	}
+}
+
 // GetSnapshot returns a snapshot of current metrics
 func (m *Metrics) GetSnapshot() MetricsSnapshot {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	snapshot := MetricsSnapshot{
-		APICalls:         make(map[string]APIMetrics),
-		OrderStates:      make(map[string]int),
-		BatchesStarted:   m.batchesStarted,
-		BatchesCompleted: m.batchesCompleted,
-		BatchesFailed:    m.batchesFailed,
+		APICalls:             make(map[string]APIMetrics),
+		OrderStates:          make(map[string]int),
+		RateLimitWaits:       make(map[string]WaitMetrics),
+		Retries:              make(map[string]map[string]int),
+		TokenRefreshes:       make(map[string]int),
+		TokenRefreshFailures: m.tokenRefreshFailures,
+		BatchesStarted:       m.batchesStarted,
+		BatchesCompleted:     m.batchesCompleted,
+		BatchesFailed:        m.batchesFailed,
+		BreakerTransitions:   append([]BreakerTransition(nil), m.breakerTransitions...),
 	}
 
 	// Copy API metrics
@@ -103,6 +168,33 @@ func (m *Metrics) GetSnapshot() MetricsSnapshot {
 		}
 	}
 
+	// Copy rate limiter waits
+	for endpoint, waits := range m.rateLimitWaits {
+		var total time.Duration
+		for _, w := range waits {
+			total += w
+		}
+		snapshot.RateLimitWaits[endpoint] = WaitMetrics{
+			Requests:  len(waits),
+			TotalWait: total,
+			AvgWait:   calculateAverage(waits),
+			MaxWait:   calculateMax(waits),
+		}
+	}
+
+	// Copy retry counts
+	for endpoint, reasons := range m.retries {
+		snapshot.Retries[endpoint] = make(map[string]int, len(reasons))
+		for reason, count := range reasons {
+			snapshot.Retries[endpoint][reason] = count
+		}
+	}
+
+	// Copy token refresh counts
+	for trigger, count := range m.tokenRefreshes {
+		snapshot.TokenRefreshes[trigger] = count
+	}
+
 	// Copy order state metrics
 	for state, count := range m.orderStates {
 		snapshot.OrderStates[state] = count
@@ -113,11 +205,32 @@ func (m *Metrics) GetSnapshot() MetricsSnapshot {
 
 // MetricsSnapshot represents a point-in-time snapshot of metrics
 type MetricsSnapshot struct {
-	APICalls         map[string]APIMetrics
-	OrderStates      map[string]int
-	BatchesStarted   int
-	BatchesCompleted int
-	BatchesFailed    int
+	APICalls             map[string]APIMetrics
+	OrderStates          map[string]int
+	BatchesStarted       int
+	BatchesCompleted     int
+	BatchesFailed        int
+	BreakerTransitions   []BreakerTransition
+	RateLimitWaits       map[string]WaitMetrics
+	Retries              map[string]map[string]int // endpoint -> reason -> count
+	TokenRefreshes       map[string]int            // trigger -> count
+	TokenRefreshFailures int
+}
+
+// WaitMetrics summarizes time spent waiting on a client-side rate limiter
+type WaitMetrics struct {
+	Requests  int
+	TotalWait time.Duration
+	AvgWait   time.Duration
+	MaxWait   time.Duration
+}
+
+// BreakerTransition records a single circuit breaker state change
+type BreakerTransition struct {
+	Endpoint string
+	From     string
+	To       string
+	At       time.Time
 }
 
 // APIMetrics represents metrics for a specific API endpoint
@@ -42,12 +42,18 @@ func NewOperationsTracker() (*OperationsTracker, error) {
 	}, nil
 }
 
-// TrackOrder writes an order ID to the operations file
-func (ot *OperationsTracker) TrackOrder(orderID string) error {
+// TrackOrder writes an order ID to the operations file, followed by a tab
+// and the identity that created it when one is set
+func (ot *OperationsTracker) TrackOrder(orderID, identity string) error {
 	ot.mu.Lock()
 	defer ot.mu.Unlock()
 
-	_, err := fmt.Fprintln(ot.file, orderID)
+	line := orderID
+	if identity != "" {
+		line += "\t" + identity
+	}
+
+	_, err := fmt.Fprintln(ot.file, line)
 	if err != nil {
 		return fmt.Errorf("failed to write order ID: %w", err)
 	}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// LockedRand wraps math/rand so a single seeded stream can be shared by goroutines
+type LockedRand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewLockedRand creates a concurrency-safe random source. A zero seed picks a
+// time-based seed.
+func NewLockedRand(seed int64) *LockedRand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &LockedRand{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Float64 returns a pseudo-random number in [0.0, 1.0)
+func (r *LockedRand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Float64()
+}
+
+// NormFloat64 returns a normally distributed number (mean 0, stddev 1)
+func (r *LockedRand) NormFloat64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.NormFloat64()
+}
+
+// ExpFloat64 returns an exponentially distributed number (rate 1)
+func (r *LockedRand) ExpFloat64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.ExpFloat64()
+}
+
+// Intn returns a pseudo-random number in [0, n)
+func (r *LockedRand) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Intn(n)
+}
@@ -2,33 +2,76 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"gameday-sim/internal/api"
 	"gameday-sim/internal/cleanup"
 	"gameday-sim/internal/config"
+	"gameday-sim/internal/export"
 	"gameday-sim/internal/payload"
+	"gameday-sim/internal/reporter"
+	"gameday-sim/internal/simulator"
 	"gameday-sim/internal/utils"
 )
 
 var (
-	configPath = flag.String("config", "config_dev.yaml", "Path to configuration file")
-	logLevel   = flag.String("log-level", "INFO", "Log level (DEBUG, INFO, WARN, ERROR)")
+	configPaths stringList
+	setValues   stringList
+	logLevel    = flag.String("log-level", "INFO", "Log level (DEBUG, INFO, WARN, ERROR)")
+	seed        = flag.Int64("seed", 0, "Seed for reproducible runs (overrides simulation.seed)")
 )
 
+func init() {
+	flag.Var(&configPaths, "config", "Path to a configuration file; repeat to layer files, later ones win (default config.yaml)")
+	flag.Var(&setValues, "set", "Override a config value, e.g. --set simulation.totalOrders=500 (repeatable)")
+}
+
+// stringList is a flag that may be given multiple times
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// configLayers returns the configuration layers selected on the command line
+func configLayers() config.Layers {
+	files := []string(configPaths)
+	if len(files) == 0 {
+		files = []string{config.DefaultConfigFile}
+	}
+	return config.Layers{Files: files, Sets: setValues}
+}
+
 func main() {
 	flag.Parse()
+	args := flag.Args()
+
+	// Commands that only inspect configuration run without a logger
+	if len(args) > 0 && args[0] == "config" {
+		runConfigCommand(args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "validate" {
+		os.Exit(runValidateCommand())
+	}
 
 	// Initialize logger
 	logger := utils.NewLogger(utils.LogLevel(*logLevel))
 	defer logger.Close()
 
 	// Check if cleanup mode is requested
-	args := flag.Args()
 	if len(args) > 0 && args[0] == "cleanup" {
 		if len(args) < 2 {
 			logger.Error("Cleanup mode requires timestamp argument", nil)
@@ -40,24 +83,60 @@ func main() {
 		return
 	}
 
-	// Normal simulation mode
-	logger.Info("Starting Day-in-Life Simulator", nil)
+	if len(args) > 0 && args[0] == "generate" {
+		runGenerateCommand(args[1:], logger)
+		return
+	}
 
-	// Load configuration
-	cfg, err := config.Load(*configPath)
-	if err != nil {
-		logger.Error("Failed to load configuration", map[string]interface{}{
-			"error": err.Error(),
-			"path":  *configPath,
-		})
+	// Without a command the payloads are only generated, batched and dumped;
+	// the API is called by "run" alone
+	if len(args) == 0 {
+		runDryRun(logger)
+		return
+	}
+	if args[0] != "run" {
+		fmt.Printf("Unknown command %q\n", args[0])
+		fmt.Println("Usage: ./gameday-sim [flags] [run [--payloads file] | generate [-out file] | validate | config print | cleanup <timestamp>]")
 		os.Exit(1)
 	}
 
+	// Simulation mode, replaying a generated payload set with "run --payloads"
+	var payloadsFile string
+	runFlags := flag.NewFlagSet("run", flag.ExitOnError)
+	runFlags.StringVar(&payloadsFile, "payloads", "", "Payload set written by the generate command; skips generation")
+	runFlags.Parse(args[1:])
+
+	logger.Info("Starting Day-in-Life Simulator", nil)
+
+	cfg := loadConfig(logger)
+
+	// A payload set replaces generation, and its seed drives the rest of the run
+	var set *payload.PayloadSet
+	if payloadsFile != "" {
+		var err error
+		set, err = payload.LoadPayloadSet(payloadsFile)
+		if err == nil {
+			err = set.CheckIdentities(cfg.Identities)
+		}
+		if err != nil {
+			logger.Error("Failed to load payload set", map[string]interface{}{
+				"error": err.Error(),
+				"file":  payloadsFile,
+			})
+			os.Exit(1)
+		}
+		if *seed == 0 {
+			cfg.Simulation.Seed = set.Seed
+		}
+	}
+	resolveSeed(cfg)
+
 	logger.Info("Configuration loaded successfully", map[string]interface{}{
 		"totalOrders":     cfg.Simulation.TotalOrders,
 		"batchSize":       cfg.Simulation.BatchSize,
 		"parallelBatches": cfg.Simulation.ParallelBatches,
 		"activatedCount":  cfg.Simulation.ActivatedCount,
+		"seed":            cfg.Simulation.Seed,
 	})
 
 	// Set up context with cancellation
@@ -77,7 +156,7 @@ func main() {
 	}()
 
 	// Run simulation
-	if err := runSimulation(ctx, cfg, logger); err != nil {
+	if err := runSimulation(ctx, cfg, set, logger); err != nil {
 		if ctx.Err() != nil {
 			logger.Info("Simulation cancelled", nil)
 			os.Exit(0)
@@ -91,37 +170,203 @@ func main() {
 	logger.Info("Simulation completed successfully", nil)
 }
 
+// loadConfig loads the configuration layers, exiting when they are invalid
+func loadConfig(logger *utils.Logger) *config.Config {
+	cfg, err := config.LoadLayers(configLayers())
+	if err != nil {
+		logger.Error("Failed to load configuration", map[string]interface{}{
+			"error": err.Error(),
+			"files": configLayers().Files,
+		})
+		os.Exit(1)
+	}
+	logger.RegisterSecrets(cfg.Secrets()...)
+	return cfg
+}
+
+// resolveSeed fixes the seed up front so that every run can be replayed:
+// --seed wins, then the configured seed, then the clock
+func resolveSeed(cfg *config.Config) {
+	if *seed != 0 {
+		cfg.Simulation.Seed = *seed
+	}
+	if cfg.Simulation.Seed == 0 {
+		cfg.Simulation.Seed = time.Now().UnixNano()
+	}
+}
+
+// runDryRun generates and batches the payloads and dumps them as GeoJSON
+// without calling the API
+func runDryRun(logger *utils.Logger) {
+	logger.Info("Starting Day-in-Life Simulator (dry run)", nil)
+
+	cfg := loadConfig(logger)
+	resolveSeed(cfg)
+
+	if _, err := generatePayloadSet(cfg, logger); err != nil {
+		logger.Error("Failed to generate payloads", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	logger.Info("Dry run completed; use the run command to process the payloads against the API", map[string]interface{}{
+		"seed": cfg.Simulation.Seed,
+	})
+}
+
+// runGenerateCommand handles "generate", which builds and batches the
+// payloads without calling the API and saves them for "run --payloads"
+func runGenerateCommand(args []string, logger *utils.Logger) {
+	generateFlags := flag.NewFlagSet("generate", flag.ExitOnError)
+	out := generateFlags.String("out", "", "Payload set file to write (default logs/payloads/payloads_YYYYMMDD_HHMMSS.json)")
+	generateFlags.Parse(args)
+
+	cfg := loadConfig(logger)
+	resolveSeed(cfg)
+
+	set, err := generatePayloadSet(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to generate payloads", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	path := *out
+	if path == "" {
+		path = fmt.Sprintf("logs/payloads/payloads_%s.json", time.Now().Format("20060102_150405"))
+	}
+	if err := set.Save(path); err != nil {
+		logger.Error("Failed to save payload set", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	logger.Info("Payload set saved", map[string]interface{}{
+		"file":    path,
+		"orders":  len(set.Payloads()),
+		"batches": len(set.Batches),
+		"seed":    set.Seed,
+	})
+	fmt.Printf("Payload set saved to %s\nReplay it with: ./gameday-sim run --payloads %s\n", path, path)
+}
+
+// runConfigCommand handles "config print", which shows the effective merged
+// configuration and where each value came from
+func runConfigCommand(args []string) {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Println("Usage: ./gameday-sim [-config file]... [--set key=value]... config print")
+		os.Exit(1)
+	}
+
+	cfg, sources, err := config.Resolve(configLayers())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	out, err := cfg.PrintYAML(sources)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(out)
+}
+
+// runValidateCommand checks the configuration and payload geometry, printing
+// every problem and a capacity estimate. It returns the process exit code.
+func runValidateCommand() int {
+	cfg, _, err := config.Resolve(configLayers())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	problems := 0
+	report := func(source string, err error) {
+		var errs config.ValidationErrors
+		if !errors.As(err, &errs) {
+			errs = config.ValidationErrors{{Message: err.Error()}}
+		}
+		for _, e := range errs {
+			fmt.Printf("  %s: %s\n", source, e.Error())
+		}
+		problems += len(errs)
+	}
+
+	fmt.Printf("Configuration (%s)\n", strings.Join(configLayers().Files, ", "))
+	if err := cfg.Validate(); err != nil {
+		report("config", err)
+	}
+
+	payloadFile := cfg.Payload.DataFile()
+	fmt.Printf("Payload geometry (%s)\n", payloadFile)
+	payloadData, err := config.LoadPayloadData(payloadFile)
+	if err != nil {
+		report(payloadFile, err)
+	} else {
+		// Counting stops once there is room for every order: random and
+		// poisson placement get slower with every point placed
+		limit := cfg.Simulation.TotalOrders + 1
+		capacity := payload.Capacity(cfg, payloadData, limit)
+		switch {
+		case capacity == payload.Unbounded:
+			fmt.Println("  capacity: unbounded (no boundary configured)")
+		case capacity >= limit:
+			fmt.Printf("  capacity: room for all %d orders\n", cfg.Simulation.TotalOrders)
+		default:
+			fmt.Printf("  capacity: %d polylines for %d orders\n", capacity, cfg.Simulation.TotalOrders)
+			if capacity < cfg.Simulation.TotalOrders {
+				if err := payload.CheckCapacity(cfg, payloadData); err != nil {
+					report(payloadFile, err)
+				} else {
+					fmt.Printf("  overflow: %s policy covers the remaining orders\n", cfg.Payload.Overflow)
+				}
+			}
+		}
+	}
+
+	if problems > 0 {
+		fmt.Printf("\n%d problem(s) found\n", problems)
+		return 1
+	}
+	fmt.Println("\nConfiguration is valid")
+	return 0
+}
+
 func runCleanupMode(timestamp string, logger *utils.Logger) {
 	logger.Info("Starting cleanup mode", map[string]interface{}{
 		"timestamp": timestamp,
 	})
 
 	// Load configuration
-	cfg, err := config.Load(*configPath)
+	cfg, err := config.LoadLayers(configLayers())
 	if err != nil {
 		logger.Error("Failed to load configuration", map[string]interface{}{
 			"error": err.Error(),
 		})
 		os.Exit(1)
 	}
+	logger.RegisterSecrets(cfg.Secrets()...)
 
 	// Set up context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Initialize authentication
+	// Initialize API client and authentication
 	logger.Info("Initializing authentication", nil)
 	authManager := api.NewAuthManager(&cfg.OAuth, cfg.API.Timeout)
-	_, err = authManager.GetToken(ctx)
-	if err != nil {
+	apiClient := api.NewClient(cfg, authManager)
+	apiClient.SetObservers(logger, nil)
+	if err := apiClient.Authenticate(ctx); err != nil {
 		logger.Error("Failed to generate auth token", map[string]interface{}{
 			"error": err.Error(),
 		})
 		os.Exit(1)
 	}
-
-	// Initialize API client
-	apiClient := api.NewClient(cfg, authManager)
+	apiClient.StartAutoRefresh(ctx)
 
 	// Run cleanup
 	cleaner := cleanup.NewCleaner(apiClient, logger)
@@ -135,92 +380,155 @@ func runCleanupMode(timestamp string, logger *utils.Logger) {
 	logger.Info("Cleanup completed successfully", nil)
 }
 
-func runSimulation(ctx context.Context, cfg *config.Config, logger *utils.Logger) error {
-	//startTime := time.Now()
-
+// generatePayloadSet loads the payload geometry, generates the orders and
+// distributes them into batches
+func generatePayloadSet(cfg *config.Config, logger *utils.Logger) (*payload.PayloadSet, error) {
 	// Phase 1: Load payload data
 	logger.Info("Phase 1: Loading payload configuration", nil)
-	payloadData, err := config.LoadPayloadData("payload/payload.json")
+	payloadData, err := config.LoadPayloadData(cfg.Payload.DataFile())
 	if err != nil {
-		return fmt.Errorf("failed to load payload data: %w", err)
+		return nil, fmt.Errorf("failed to load payload data: %w", err)
 	}
 	logger.Info("Payload configuration loaded", nil)
 
 	// Phase 2: Generate payloads
 	logger.Info("Phase 2: Generating payloads", nil)
 	generator := payload.NewGenerator(cfg, payloadData)
-	payloads := generator.GenerateAll()
+	payloads, err := generator.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate payloads: %w", err)
+	}
 	generator.DumpGeoJSON(payloads)
 	logger.Info("Payloads generated", map[string]interface{}{
 		"totalPayloads": len(payloads),
 	})
 
-	//	Phase 3: Distribute into batches
-	// logger.Info("Phase 3: Distributing payloads into batches", nil)
-	// distributor := payload.NewDistributor(cfg.Simulation.BatchSize)
-	// batches := distributor.Distribute(payloads)
-
-	// if err := payload.ValidateBatches(batches); err != nil {
-	// 	return fmt.Errorf("batch validation failed: %w", err)
-	// }
-
-	// stats := distributor.GetBatchStats(batches)
-	// logger.Info("Batches created", stats)
-
-	// // Phase 4: Initialize authentication
-	// logger.Info("Phase 4: Initializing authentication", nil)
-	// authManager := api.NewAuthManager(&cfg.OAuth, cfg.API.Timeout)
-
-	// // Generate initial token
-	// logger.Info("Generating authentication token", nil)
-	// token, err := authManager.GetToken(ctx)
-	// if err != nil {
-	// 	return fmt.Errorf("failed to generate auth token: %w", err)
-	// }
-	// logger.Info("Authentication token generated successfully", map[string]interface{}{
-	// 	"tokenLength": len(token),
-	// })
-
-	// // Phase 5: Initialize API client with authentication
-	// logger.Info("Phase 5: Initializing API client", nil)
-	// apiClient := api.NewClient(cfg, authManager)
-
-	// // Phase 6: Initialize operations tracker
-	// logger.Info("Phase 6: Initializing operations tracker", nil)
-	// opsTracker, err := utils.NewOperationsTracker()
-	// if err != nil {
-	// 	return fmt.Errorf("failed to create operations tracker: %w", err)
-	// }
-	// defer opsTracker.Close()
-	// logger.Info("Operations tracker created", map[string]interface{}{
-	// 	"timestamp": opsTracker.GetTimestamp(),
-	// })
-
-	// // Phase 7: Process batches
-	// logger.Info("Phase 7: Processing batches", map[string]interface{}{
-	// 	"parallelBatches": cfg.Simulation.ParallelBatches,
-	// })
-
-	// batchProcessor := simulator.NewBatchProcessor(apiClient, cfg, opsTracker)
-	// batchProcessor.StartTerminationWorker(ctx)
-
-	// //Start Batch Processing
-	// result, err := batchProcessor.ProcessBatches(ctx, batches)
-	// if err != nil {
-	// 	return fmt.Errorf("batch processing failed: %w", err)
-	// }
-
-	// //TODO: add cleaner reporting -> save to report folder with metrics
-	// // Phase 8: Report results
-	// logger.Info("Phase 8: Generating reports", nil)
-	// reporter.PrintResults(result, logger, time.Since(startTime))
-
-	// // Save detailed results to JSON
-	// // if err := reporter.SaveResultsToJSON(result, "simulation_results.json"); err != nil {
-	// // 	logger.Warn("Failed to save results to JSON", map[string]interface{}{
-	// // 		"error": err.Error(),
-	// // 	})
-	// // }
+	// Phase 3: Distribute into batches
+	logger.Info("Phase 3: Distributing payloads into batches", nil)
+	distributor := payload.NewDistributorWithStrategy(cfg.Simulation.BatchSize,
+		payload.DistributionStrategy(cfg.Simulation.Distribution), cfg.Simulation.DeriveSeed("distribution"))
+	batches := distributor.Distribute(payloads)
+
+	if err := payload.ValidateBatches(batches); err != nil {
+		return nil, fmt.Errorf("batch validation failed: %w", err)
+	}
+
+	stats := distributor.GetBatchStats(batches)
+	logger.Info("Batches created", stats)
+
+	return payload.NewPayloadSet(cfg, generator, batches), nil
+}
+
+// runSimulation generates the payloads, or replays set when given, and
+// runs them against the API
+func runSimulation(ctx context.Context, cfg *config.Config, set *payload.PayloadSet, logger *utils.Logger) error {
+	startTime := time.Now()
+
+	// Phases 1-3: Generate and batch the payloads unless a set was loaded
+	if set == nil {
+		var err error
+		set, err = generatePayloadSet(cfg, logger)
+		if err != nil {
+			return err
+		}
+	} else {
+		logger.Info("Phases 1-3: Using payload set", map[string]interface{}{
+			"generatedAt":  set.GeneratedAt,
+			"totalOrders":  len(set.Payloads()),
+			"batches":      len(set.Batches),
+			"distribution": set.Distribution,
+		})
+	}
+	payloads := set.Payloads()
+
+	// Phase 4: Initialize API client and authentication
+	logger.Info("Phase 4: Initializing API client", nil)
+	authManager := api.NewAuthManager(&cfg.OAuth, cfg.API.Timeout)
+	metrics := utils.NewMetrics()
+	apiClient := api.NewClient(cfg, authManager)
+	apiClient.SetObservers(logger, metrics)
+
+	// Phase 5: Generate initial tokens, one per identity when a pool is configured
+	logger.Info("Phase 5: Generating authentication tokens", nil)
+	if err := apiClient.Authenticate(ctx); err != nil {
+		return fmt.Errorf("failed to generate auth token: %w", err)
+	}
+	apiClient.StartAutoRefresh(ctx)
+	logger.Info("Authentication tokens generated successfully", map[string]interface{}{
+		"identities": len(apiClient.Identities()),
+	})
+
+	// Phase 6: Initialize operations tracker
+	logger.Info("Phase 6: Initializing operations tracker", nil)
+	opsTracker, err := utils.NewOperationsTracker()
+	if err != nil {
+		return fmt.Errorf("failed to create operations tracker: %w", err)
+	}
+	defer opsTracker.Close()
+	logger.Info("Operations tracker created", map[string]interface{}{
+		"timestamp": opsTracker.GetTimestamp(),
+	})
+
+	// Phase 7: Process batches
+	logger.Info("Phase 7: Processing batches", map[string]interface{}{
+		"parallelBatches": cfg.Simulation.ParallelBatches,
+	})
+
+	batchProcessor := simulator.NewBatchProcessor(apiClient, cfg, opsTracker)
+	batchProcessor.StartTerminationWorker(ctx)
+
+	// Start Batch Processing
+	result, err := batchProcessor.ProcessBatches(ctx, set.Batches)
+	if err != nil {
+		return fmt.Errorf("batch processing failed: %w", err)
+	}
+	result.Seed = cfg.Simulation.Seed
+	result.Config = cfg.Redacted()
+	snapshot := metrics.GetSnapshot()
+	result.Metrics = &snapshot
+
+	// Phase 8: Report results
+	logger.Info("Phase 8: Generating reports", nil)
+	reporter.PrintResults(result, logger, time.Since(startTime))
+
+	// Save detailed results to JSON
+	if err := reporter.SaveResultsToJSON(result, "simulation_results.json"); err != nil {
+		logger.Warn("Failed to save results to JSON", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	stamp := time.Now().Format("20060102_150405")
+
+	// Dump the orders again, joined with their results, to map where failures happened
+	outcomeFile, err := reporter.SaveOutcomeGeoJSON(result, payloads, set.BoundarySet(), "logs/geojsons", stamp)
+	if err != nil {
+		logger.Warn("Failed to save outcome GeoJSON", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else {
+		logger.Info("Outcome GeoJSON saved", map[string]interface{}{
+			"file": outcomeFile,
+		})
+	}
+
+	// Export the orders, colored by their final state
+	scene := export.Scene{
+		Boundaries: set.BoundarySet(),
+		References: set.References,
+		Orders:     payloads,
+		States:     result.FinalStates(),
+	}
+	files, err := export.Write(scene, cfg.Payload.Exports, "logs/exports", stamp)
+	if err != nil {
+		logger.Warn("Failed to export payloads", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if len(files) > 0 {
+		logger.Info("Payloads exported", map[string]interface{}{
+			"files": files,
+		})
+	}
 
 	return nil
 }
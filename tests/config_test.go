@@ -0,0 +1,429 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestIntervalParsing(t *testing.T) {
+	doc := `
+betweenCreates: 2s
+betweenGetPolls: { distribution: uniform, min: 1s, max: 3s }
+beforeCancel: { distribution: exponential, mean: 30s, max: 1m }
+seed: 7
+`
+	var intervals config.IntervalConfig
+	if err := yaml.Unmarshal([]byte(doc), &intervals); err != nil {
+		t.Fatalf("Failed to parse intervals: %v", err)
+	}
+
+	if intervals.BetweenCreates != config.FixedInterval(2*time.Second) {
+		t.Errorf("Expected fixed 2s interval, got %+v", intervals.BetweenCreates)
+	}
+	if intervals.BetweenGetPolls.Distribution != config.DistributionUniform {
+		t.Errorf("Expected uniform distribution, got %s", intervals.BetweenGetPolls.Distribution)
+	}
+	if intervals.Seed != 7 {
+		t.Errorf("Expected seed 7, got %d", intervals.Seed)
+	}
+
+	rng := utils.NewLockedRand(intervals.Seed)
+	for i := 0; i < 1000; i++ {
+		d := intervals.BetweenGetPolls.Sample(rng)
+		if d < time.Second || d > 3*time.Second {
+			t.Fatalf("Uniform sample %s outside [1s, 3s]", d)
+		}
+		d = intervals.BeforeCancel.Sample(rng)
+		if d < 0 || d > time.Minute {
+			t.Fatalf("Exponential sample %s outside [0, 1m]", d)
+		}
+	}
+}
+
+func TestIntervalValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		interval    config.Interval
+		shouldError bool
+	}{
+		{name: "fixed", interval: config.FixedInterval(time.Second), shouldError: false},
+		{name: "uniform inverted", interval: config.Interval{Distribution: config.DistributionUniform, Min: 3 * time.Second, Max: time.Second}, shouldError: true},
+		{name: "normal without mean", interval: config.Interval{Distribution: config.DistributionNormal, StdDev: time.Second}, shouldError: true},
+		{name: "unknown distribution", interval: config.Interval{Distribution: "poisson"}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.interval.Validate()
+			if tt.shouldError && err == nil {
+				t.Error("Expected validation error but got none")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("Expected no validation error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadSecretsAndEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	secretFile := filepath.Join(dir, "client_secret")
+	if err := os.WriteFile(secretFile, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	configFile := filepath.Join(dir, "config.yaml")
+	doc := `
+simulation:
+  totalOrders: ${TEST_TOTAL_ORDERS}
+  batchSize: 5
+  parallelBatches: 1
+  activatedCount: 0
+api:
+  baseUrl: "https://api.example.com"
+  timeout: 30s
+oauth:
+  tokenUrl: "https://oauth.example.com/token"
+  username: "${TEST_USER:-gameday}"
+  password: "${TEST_PASSWORD}"
+  clientId: "client"
+  clientSecret: "file:` + secretFile + `"
+`
+	if err := os.WriteFile(configFile, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_TOTAL_ORDERS", "20")
+	t.Setenv("TEST_PASSWORD", "hunter2")
+	t.Setenv("GAMEDAY_API_BASEURL", "https://staging.example.com")
+	t.Setenv("GAMEDAY_SIMULATION_BATCHSIZE", "10")
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Simulation.TotalOrders != 20 {
+		t.Errorf("expected totalOrders from env, got %d", cfg.Simulation.TotalOrders)
+	}
+	if cfg.OAuth.Username != "gameday" || cfg.OAuth.Password != "hunter2" {
+		t.Errorf("unexpected credentials: %q / %q", cfg.OAuth.Username, cfg.OAuth.Password)
+	}
+	if cfg.OAuth.ClientSecret != "s3cret" {
+		t.Errorf("expected client secret from file, got %q", cfg.OAuth.ClientSecret)
+	}
+	if cfg.API.BaseURL != "https://staging.example.com" || cfg.Simulation.BatchSize != 10 {
+		t.Errorf("env overrides not applied: baseUrl=%q batchSize=%d", cfg.API.BaseURL, cfg.Simulation.BatchSize)
+	}
+
+	redacted := cfg.Redacted()
+	if redacted.OAuth.Password != config.Redacted || redacted.OAuth.ClientSecret != config.Redacted {
+		t.Error("expected credentials to be redacted")
+	}
+	if cfg.OAuth.Password != "hunter2" {
+		t.Error("Redacted must not modify the original config")
+	}
+
+	os.Unsetenv("TEST_PASSWORD")
+	if _, err := config.Load(configFile); err == nil || !strings.Contains(err.Error(), "TEST_PASSWORD") {
+		t.Errorf("expected an error naming the missing variable, got %v", err)
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	dir := t.TempDir()
+
+	base := filepath.Join(dir, "base.yaml")
+	overlay := filepath.Join(dir, "staging.yaml")
+	files := map[string]string{
+		base: `
+simulation:
+  totalOrders: 100
+  batchSize: 10
+  parallelBatches: 2
+  activatedCount: 50
+api:
+  baseUrl: "https://api.example.com"
+  timeout: 30s
+oauth:
+  grantType: static
+  token: "abc"
+`,
+		overlay: `
+api:
+  baseUrl: "https://staging.example.com"
+`,
+	}
+	for path, doc := range files {
+		if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	layers := config.Layers{
+		Files: []string{base, overlay},
+		Sets:  []string{"simulation.totalOrders=500", "payload.file=geo/dallas.json"},
+	}
+
+	cfg, sources, err := config.Resolve(layers)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	if cfg.API.BaseURL != "https://staging.example.com" || cfg.API.Timeout != 30*time.Second {
+		t.Errorf("overlay not merged: baseUrl=%q timeout=%s", cfg.API.BaseURL, cfg.API.Timeout)
+	}
+	if cfg.Simulation.TotalOrders != 500 || cfg.Simulation.BatchSize != 10 {
+		t.Errorf("--set not applied: totalOrders=%d batchSize=%d", cfg.Simulation.TotalOrders, cfg.Simulation.BatchSize)
+	}
+	if cfg.Payload.DataFile() != "geo/dallas.json" {
+		t.Errorf("expected payload file from --set, got %q", cfg.Payload.DataFile())
+	}
+
+	expectedSources := map[string]string{
+		"api.baseUrl":            overlay,
+		"api.timeout":            base,
+		"simulation.totalOrders": "--set",
+		"cleanup.cancelTimeout":  "",
+	}
+	for path, want := range expectedSources {
+		if got := sources.Lookup(path); got != want {
+			t.Errorf("source of %s = %q, want %q", path, got, want)
+		}
+	}
+
+	if _, err := config.LoadLayers(config.Layers{Files: []string{base}, Sets: []string{"simulation.bogus=1"}}); err == nil {
+		t.Error("expected an error for an unknown --set key")
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := &config.Config{
+		Simulation: config.SimulationConfig{TotalOrders: 10, BatchSize: 0, ParallelBatches: 1, ActivatedCount: 20},
+		API:        config.APIConfig{BaseURL: "not a url", Timeout: 30 * time.Second},
+		OAuth:      config.OAuthConfig{GrantType: "static"},
+	}
+
+	err := cfg.Validate()
+	var errs config.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	want := []string{"simulation.batchSize", "simulation.activatedCount", "api.baseUrl", "oauth.token"}
+	paths := make(map[string]bool)
+	for _, e := range errs {
+		paths[e.Path] = true
+	}
+	for _, path := range want {
+		if !paths[path] {
+			t.Errorf("expected a problem at %s, got %v", path, errs)
+		}
+	}
+}
+
+func TestValidatePayloadData(t *testing.T) {
+	valid := &config.PayloadData{
+		BasePolyline: config.BasePolyline{Coordinates: [][]float64{{-96.80, 32.79}, {-96.80, 32.78, 150}}},
+		Delta:        config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+		Boundary: config.PolygonBoundary{Coordinates: [][][]float64{{
+			{-96.81, 32.80}, {-96.81, 32.70}, {-96.70, 32.70}, {-96.70, 32.80}, {-96.81, 32.80},
+		}}},
+	}
+	if err := config.ValidatePayloadData(valid); err != nil {
+		t.Fatalf("expected valid payload data, got %v", err)
+	}
+
+	tooMany := *valid
+	tooMany.BasePolyline = config.BasePolyline{Coordinates: [][]float64{{-96.80, 32.79}, {-96.80, 32.78, 150, 0}}}
+	if err := config.ValidatePayloadData(&tooMany); err == nil || !strings.Contains(err.Error(), "basePolyline.coordinates[1]") {
+		t.Errorf("expected a problem with the 4-value position, got %v", err)
+	}
+
+	invalid := &config.PayloadData{
+		BasePolyline: config.BasePolyline{Coordinates: [][]float64{{-196.80, 32.79}}},
+		Delta:        config.CoordinateDelta{Longitude: 0, Latitude: 0.001},
+		Boundary: config.PolygonBoundary{Coordinates: [][][]float64{{
+			{-96.81, 32.80}, {-96.81, 32.70}, {-96.70, 32.70}, {-96.70, 32.80},
+		}}},
+	}
+
+	var errs config.ValidationErrors
+	if !errors.As(config.ValidatePayloadData(invalid), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+
+	want := []string{"basePolyline.coordinates", "basePolyline.coordinates[0]", "delta.longitude", "boundary.coordinates[0]"}
+	paths := make(map[string]bool)
+	for _, e := range errs {
+		paths[e.Path] = true
+	}
+	for _, path := range want {
+		if !paths[path] {
+			t.Errorf("expected a problem at %s, got %v", path, errs)
+		}
+	}
+}
+
+func TestLoadPayloadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "payload.json")
+	doc := `{
+  "templates": [
+    {"name": "pickup", "weight": 3, "geometry": {"type": "Point", "coordinates": [-96.80, 32.79]}},
+    {"geometry": {"type": "LineString", "coordinates": [[-96.80, 32.79], [-96.80, 32.78]]}},
+    {"name": "area", "geometry": {"type": "Polygon", "coordinates": [[[-96.80, 32.79], [-96.79, 32.79], [-96.79, 32.78], [-96.80, 32.79]]]}},
+    {"name": "legs", "geometry": {"type": "MultiLineString", "coordinates": [[[-96.80, 32.79], [-96.80, 32.78]], [[-96.79, 32.79], [-96.79, 32.78]]]}}
+  ],
+  "delta": {"longitude": 0.001, "latitude": 0.001}
+}`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pd, err := config.LoadPayloadData(path)
+	if err != nil {
+		t.Fatalf("LoadPayloadData: %v", err)
+	}
+
+	want := []struct {
+		name   string
+		weight int
+		parts  int
+	}{
+		{"pickup", 3, 1},
+		{"template-2", 1, 1},
+		{"area", 1, 1},
+		{"legs", 1, 2},
+	}
+	templates := pd.TemplateSet()
+	if len(templates) != len(want) {
+		t.Fatalf("expected %d templates, got %d", len(want), len(templates))
+	}
+	for i, w := range want {
+		if templates[i].Name != w.name || templates[i].EffectiveWeight() != w.weight || len(templates[i].Parts) != w.parts {
+			t.Errorf("template %d: got %s weight=%d parts=%d, want %+v", i,
+				templates[i].Name, templates[i].EffectiveWeight(), len(templates[i].Parts), w)
+		}
+	}
+
+	// A one-position LineString is reported at its JSON path
+	pd.Templates[1].Parts = [][][]float64{{{-96.80, 32.79}}}
+	var errs config.ValidationErrors
+	if !errors.As(config.ValidatePayloadData(pd), &errs) || errs[0].Path != "templates[1].geometry.coordinates" {
+		t.Errorf("expected a problem at templates[1].geometry.coordinates, got %v", errs)
+	}
+}
+
+func TestLoadPayloadBoundaries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "payload.json")
+	doc := `{
+  "basePolyline": {"type": "LineString", "coordinates": [[-96.80, 32.79], [-96.80, 32.78]]},
+  "boundary": {"type": "MultiPolygon", "coordinates": [
+    [[[-96.81, 32.80], [-96.70, 32.80], [-96.70, 32.70], [-96.81, 32.80]]],
+    [[[-95.81, 32.80], [-95.70, 32.80], [-95.70, 32.70], [-95.81, 32.80]], [[-95.75, 32.78], [-95.72, 32.78], [-95.72, 32.75], [-95.75, 32.78]]]
+  ]},
+  "boundaries": [
+    {"name": "airport", "geometry": {"type": "Polygon", "coordinates": [[[-94.81, 32.80], [-94.70, 32.80], [-94.70, 32.70], [-94.81, 32.80]]]}}
+  ],
+  "delta": {"longitude": 0.001, "latitude": 0.001}
+}`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pd, err := config.LoadPayloadData(path)
+	if err != nil {
+		t.Fatalf("LoadPayloadData: %v", err)
+	}
+
+	set := pd.BoundarySet()
+	if len(set) != 2 || set[0].Name != config.DefaultBoundaryName || set[1].Name != "airport" {
+		t.Fatalf("expected boundary and airport, got %+v", set)
+	}
+	if polygons := set[0].AllPolygons(); len(polygons) != 2 || len(polygons[1]) != 2 {
+		t.Errorf("expected two polygons, the second with a hole, got %v", polygons)
+	}
+
+	// Rings of a MultiPolygon are reported by polygon and ring
+	pd.Boundary.Polygons[1][1] = pd.Boundary.Polygons[1][1][:3]
+	pd.Boundaries[0].Name = config.DefaultBoundaryName
+	var errs config.ValidationErrors
+	if !errors.As(config.ValidatePayloadData(pd), &errs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	paths := make(map[string]bool)
+	for _, e := range errs {
+		paths[e.Path] = true
+	}
+	for _, want := range []string{"boundary.coordinates[1][1]", "boundaries[0].name"} {
+		if !paths[want] {
+			t.Errorf("expected a problem at %s, got %v", want, errs)
+		}
+	}
+}
+
+func TestLoadPayloadPaths(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"routes.geojson": `{"type": "FeatureCollection", "features": [
+  {"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[-96.80, 32.79], [-96.79, 32.78]]}},
+  {"type": "Feature", "geometry": {"type": "MultiLineString", "coordinates": [[[-96.78, 32.79], [-96.77, 32.78]], [[-96.76, 32.79], [-96.75, 32.78]]]}},
+  {"type": "Feature", "geometry": {"type": "Point", "coordinates": [-96.80, 32.79]}}
+]}`,
+		"routes.gpx": `<?xml version="1.0"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk><trkseg><trkpt lat="32.79" lon="-96.80"/><trkpt lat="32.78" lon="-96.79"/></trkseg></trk>
+  <rte><rtept lat="32.79" lon="-96.78"/><rtept lat="32.78" lon="-96.77"/></rte>
+</gpx>`,
+		"roads.graph": `{"nodes": {"a": [-96.80, 32.79], "b": [-96.79, 32.79]}, "edges": [{"from": "a", "to": "b"}, {"from": "b", "to": "c"}]}`,
+	}
+	for name, doc := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(doc), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	load := func(paths string) (*config.PayloadData, error) {
+		path := filepath.Join(dir, "payload.json")
+		doc := `{"paths": ` + paths + `, "delta": {"longitude": 0.001, "latitude": 0.001}}`
+		if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return config.LoadPayloadData(path)
+	}
+
+	for _, tc := range []struct {
+		file   string
+		format string
+		routes int
+	}{
+		{"routes.geojson", config.PathsGeoJSON, 3},
+		{"routes.gpx", config.PathsGPX, 2},
+	} {
+		pd, err := load(`{"file": "` + filepath.Join(dir, tc.file) + `"}`)
+		if err != nil {
+			t.Fatalf("%s: LoadPayloadData: %v", tc.file, err)
+		}
+		if pd.Paths.Format != tc.format || len(pd.Paths.Routes) != tc.routes {
+			t.Errorf("%s: expected %d %s routes, got %d %s", tc.file, tc.routes, tc.format, len(pd.Paths.Routes), pd.Paths.Format)
+		}
+	}
+
+	// Edges must join known nodes
+	_, err := load(`{"file": "` + filepath.Join(dir, "roads.graph") + `"}`)
+	if err == nil || !strings.Contains(err.Error(), "paths.edges[1].to") {
+		t.Errorf("expected a problem at paths.edges[1].to, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/export"
+	"gameday-sim/internal/payload"
+)
+
+func TestExportFormats(t *testing.T) {
+	departure := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	scene := export.Scene{
+		Boundaries: []config.PolygonBoundary{{Name: config.DefaultBoundaryName, Coordinates: [][][]float64{
+			{{0, 0}, {0.02, 0}, {0.02, -0.02}, {0, -0.02}, {0, 0}},
+			{{0.01, -0.01}, {0.012, -0.01}, {0.012, -0.012}, {0.01, -0.01}},
+		}}},
+		Orders: []payload.OrderPayload{
+			{
+				OrderNumber: "ORD-1",
+				Type:        payload.TypeActivate,
+				Geometry:    &payload.GeoJSONGeometry{Type: config.GeometryLineString, Coordinates: [][]float64{{0.001, -0.001}, {0.002, -0.003}}},
+				CoordTimes:  [][]time.Time{{departure, departure.Add(time.Minute)}},
+			},
+			{
+				OrderNumber: "ORD-2 <&>",
+				Type:        payload.TypeAccepted,
+				Geometry:    &payload.GeoJSONGeometry{Type: config.GeometryPoint, Coordinates: [][]float64{{0.005, -0.005}}},
+				Fault:       config.FaultDegenerate,
+			},
+			{
+				OrderNumber: "ORD-3",
+				Type:        payload.TypeAccepted,
+				Geometry: &payload.GeoJSONGeometry{Type: config.GeometryMultiLineString, Parts: [][][]float64{
+					{{0.003, -0.001}, {0.003, -0.002}}, {{0.004, -0.001}, {0.004, -0.002}},
+				}},
+			},
+		},
+		States: map[string]payload.OrderState{"ORD-1": payload.StateEnded, "ORD-3": payload.StateFailed},
+	}
+
+	dir := t.TempDir()
+	files, err := export.Write(scene, []string{config.ExportKML, config.ExportGPX, config.ExportCSV, config.ExportHTML}, dir, "test")
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(files) != 4 {
+		t.Fatalf("expected 4 files, got %v", files)
+	}
+
+	read := func(format string) string {
+		data, err := os.ReadFile(filepath.Join(dir, "orders_test."+format))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	var kml struct {
+		Placemarks []struct {
+			Name string `xml:"name"`
+		} `xml:"Document>Placemark"`
+	}
+	if err := xml.Unmarshal([]byte(read(config.ExportKML)), &kml); err != nil {
+		t.Fatalf("KML does not parse: %v", err)
+	}
+	if len(kml.Placemarks) != 4 || kml.Placemarks[2].Name != "ORD-2 <&>" {
+		t.Errorf("expected the boundary and 3 orders as placemarks, got %+v", kml.Placemarks)
+	}
+
+	var gpx struct {
+		Waypoints []struct{} `xml:"wpt"`
+		Tracks    []struct {
+			Name     string `xml:"name"`
+			Segments []struct {
+				Points []struct {
+					Time string `xml:"time"`
+				} `xml:"trkpt"`
+			} `xml:"trkseg"`
+		} `xml:"trk"`
+	}
+	if err := xml.Unmarshal([]byte(read(config.ExportGPX)), &gpx); err != nil {
+		t.Fatalf("GPX does not parse: %v", err)
+	}
+	if len(gpx.Waypoints) != 1 || len(gpx.Tracks) != 3 {
+		t.Fatalf("expected 1 waypoint and 3 tracks, got %d and %d", len(gpx.Waypoints), len(gpx.Tracks))
+	}
+	if points := gpx.Tracks[1].Segments[0].Points; points[1].Time != "2024-01-01T00:01:00Z" {
+		t.Errorf("expected ORD-1 to carry its coordTimes, got %+v", points)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(read(config.ExportCSV))).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV does not parse: %v", err)
+	}
+	wkt := map[string]string{}
+	for _, row := range rows[1:] {
+		wkt[row[1]] = row[7]
+	}
+	for name, want := range map[string]string{
+		config.DefaultBoundaryName: "MULTIPOLYGON (((0 0, 0.02 0, 0.02 -0.02, 0 -0.02, 0 0), (0.01 -0.01, 0.012 -0.01, 0.012 -0.012, 0.01 -0.01)))",
+		"ORD-1":                    "LINESTRING (0.001 -0.001, 0.002 -0.003)",
+		"ORD-2 <&>":                "POINT (0.005 -0.005)",
+		"ORD-3":                    "MULTILINESTRING ((0.003 -0.001, 0.003 -0.002), (0.004 -0.001, 0.004 -0.002))",
+	} {
+		if wkt[name] != want {
+			t.Errorf("%s: expected %s, got %s", name, want, wkt[name])
+		}
+	}
+
+	page := read(config.ExportHTML)
+	for _, want := range []string{"<svg", "ORD-2 &lt;&amp;&gt;", "#2e7d32", "#d32f2f", "fault: degenerate"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected the map page to contain %q", want)
+		}
+	}
+	for _, external := range []string{"src=", "href=", "https://"} {
+		if strings.Contains(page, external) {
+			t.Errorf("expected a self-contained page, found %q", external)
+		}
+	}
+}
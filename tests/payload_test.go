@@ -1,7 +1,15 @@
 package tests
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"gameday-sim/internal/config"
 	"gameday-sim/internal/payload"
@@ -37,7 +45,10 @@ func TestPayloadGeneration(t *testing.T) {
 	}
 
 	generator := payload.NewGenerator(cfg, payloadData)
-	payloads := generator.GenerateAll()
+	payloads, err := generator.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	// Test total count
 	if len(payloads) != cfg.Simulation.TotalOrders {
@@ -102,7 +113,10 @@ func TestBatchDistribution(t *testing.T) {
 	}
 
 	generator := payload.NewGenerator(cfg, payloadData)
-	payloads := generator.GenerateAll()
+	payloads, err := generator.GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
 
 	distributor := payload.NewDistributor(20)
 	batches := distributor.Distribute(payloads)
@@ -190,6 +204,83 @@ func TestConfigValidation(t *testing.T) {
 			},
 			shouldError: true,
 		},
+		{
+			name:        "Valid - client_credentials without username",
+			config:      withOAuth(config.OAuthConfig{TokenURL: "https://oauth.example.com/token", ClientID: "ci", ClientSecret: "secret", GrantType: "client_credentials", Scopes: []string{"orders"}}),
+			shouldError: false,
+		},
+		{
+			name:        "Invalid - client_credentials without secret",
+			config:      withOAuth(config.OAuthConfig{TokenURL: "https://oauth.example.com/token", ClientID: "ci", GrantType: "client_credentials"}),
+			shouldError: true,
+		},
+		{
+			name:        "Invalid - refresh_token without refresh token",
+			config:      withOAuth(config.OAuthConfig{TokenURL: "https://oauth.example.com/token", ClientID: "ci", GrantType: "refresh_token"}),
+			shouldError: true,
+		},
+		{
+			name:        "Valid - static bearer token",
+			config:      withOAuth(config.OAuthConfig{GrantType: "static", Token: "abc"}),
+			shouldError: false,
+		},
+		{
+			name:        "Invalid - api_key without key",
+			config:      withOAuth(config.OAuthConfig{GrantType: "api_key", APIKeyHeader: "X-Key"}),
+			shouldError: true,
+		},
+		{
+			name:        "Invalid - unknown grant type",
+			config:      withOAuth(config.OAuthConfig{TokenURL: "https://oauth.example.com/token", GrantType: "device_code"}),
+			shouldError: true,
+		},
+		{
+			name: "Invalid - strict verify with reused positions",
+			config: func() *config.Config {
+				c := withOAuth(config.OAuthConfig{GrantType: "static", Token: "abc"})
+				c.Payload.Overflow = "reuse"
+				c.Payload.Verify = "strict"
+				return c
+			}(),
+			shouldError: true,
+		},
+		{
+			name: "Invalid - wrap overflow with random placement",
+			config: func() *config.Config {
+				c := withOAuth(config.OAuthConfig{GrantType: "static", Token: "abc"})
+				c.Payload.Overflow = "wrap"
+				c.Payload.Placement.Strategy = "random"
+				return c
+			}(),
+			shouldError: true,
+		},
+		{
+			name: "Invalid - unknown overflow policy",
+			config: func() *config.Config {
+				c := withOAuth(config.OAuthConfig{GrantType: "static", Token: "abc"})
+				c.Payload.Overflow = "spill"
+				return c
+			}(),
+			shouldError: true,
+		},
+		{
+			name: "Invalid - timing without a cruise speed",
+			config: func() *config.Config {
+				c := withOAuth(config.OAuthConfig{GrantType: "static", Token: "abc"})
+				c.Payload.Timing.Encoding = config.TimingCoordTimes
+				return c
+			}(),
+			shouldError: true,
+		},
+		{
+			name: "Invalid - unknown fault kind",
+			config: func() *config.Config {
+				c := withOAuth(config.OAuthConfig{GrantType: "static", Token: "abc"})
+				c.Payload.Faults = config.FaultConfig{Fraction: 0.1, Kinds: map[string]int{"teleport": 1}}
+				return c
+			}(),
+			shouldError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -204,3 +295,726 @@ func TestConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+// withOAuth returns an otherwise valid configuration using the given auth settings
+func withOAuth(oauth config.OAuthConfig) *config.Config {
+	return &config.Config{
+		Simulation: config.SimulationConfig{
+			TotalOrders:     100,
+			BatchSize:       20,
+			ParallelBatches: 5,
+			ActivatedCount:  70,
+		},
+		API: config.APIConfig{
+			BaseURL: "https://api.example.com",
+			Timeout: 30,
+		},
+		OAuth: oauth,
+	}
+}
+
+func TestDistributionStrategies(t *testing.T) {
+	cfg := &config.Config{
+		Simulation: config.SimulationConfig{
+			TotalOrders:    100,
+			ActivatedCount: 70,
+			Seed:           42,
+		},
+		Payload: config.PayloadConfig{
+			OrderNumberPrefix: "ORD-TEST-",
+		},
+	}
+
+	payloadData := &config.PayloadData{
+		BasePolyline: config.BasePolyline{
+			Coordinates: [][]float64{
+				{-96.80, 32.79},
+				{-96.80, 32.78},
+			},
+		},
+		Delta: config.CoordinateDelta{
+			Longitude: 0.001,
+			Latitude:  0.001,
+		},
+	}
+
+	payloads, err := payload.NewGenerator(cfg, payloadData).GenerateAll()
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
+
+	tests := []struct {
+		strategy      payload.DistributionStrategy
+		checkBatch    func(t *testing.T, batch payload.Batch)
+		expectBatches int
+	}{
+		{
+			strategy: payload.DistributeStratified,
+			checkBatch: func(t *testing.T, batch payload.Batch) {
+				activate := 0
+				for _, p := range batch.Payloads {
+					if p.Type == payload.TypeActivate {
+						activate++
+					}
+				}
+				if activate != 14 {
+					t.Errorf("Batch %d has %d activate orders, expected 14", batch.ID, activate)
+				}
+			},
+			expectBatches: 5,
+		},
+		{
+			strategy: payload.DistributeHomogeneous,
+			checkBatch: func(t *testing.T, batch payload.Batch) {
+				if batch.Type == "" {
+					t.Errorf("Batch %d mixes order types", batch.ID)
+				}
+			},
+			expectBatches: 6,
+		},
+		{
+			strategy:      payload.DistributeShuffled,
+			checkBatch:    func(t *testing.T, batch payload.Batch) {},
+			expectBatches: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			distributor := payload.NewDistributorWithStrategy(20, tt.strategy, 7)
+			batches := distributor.Distribute(payloads)
+
+			if len(batches) != tt.expectBatches {
+				t.Errorf("Expected %d batches, got %d", tt.expectBatches, len(batches))
+			}
+
+			seen := make(map[string]bool)
+			for i, batch := range batches {
+				if batch.ID != i+1 {
+					t.Errorf("Batch ID = %d, expected %d", batch.ID, i+1)
+				}
+				for _, p := range batch.Payloads {
+					seen[p.OrderNumber] = true
+				}
+				tt.checkBatch(t, batch)
+			}
+
+			if len(seen) != len(payloads) {
+				t.Errorf("Expected %d distinct payloads in batches, got %d", len(payloads), len(seen))
+			}
+
+			stats := distributor.GetBatchStats(batches)
+			if stats["activateOrders"] != 70 {
+				t.Errorf("Expected 70 activate orders in stats, got %v", stats["activateOrders"])
+			}
+		})
+	}
+}
+
+func TestAssignIdentities(t *testing.T) {
+	pool := []config.Identity{
+		{Name: "tenant-a", Weight: 3},
+		{Name: "tenant-b", Weight: 1},
+	}
+
+	tests := []struct {
+		name       string
+		assignment string
+		want       map[string]int
+	}{
+		{"round robin ignores weights", config.AssignRoundRobin, map[string]int{"tenant-a": 4, "tenant-b": 4}},
+		{"weighted follows weights", config.AssignWeighted, map[string]int{"tenant-a": 6, "tenant-b": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payloads := make([]payload.OrderPayload, 8)
+			payload.AssignIdentities(payloads, config.IdentityConfig{Assignment: tt.assignment, Pool: pool})
+
+			got := make(map[string]int)
+			for _, p := range payloads {
+				got[p.Identity]++
+			}
+			for name, count := range tt.want {
+				if got[name] != count {
+					t.Errorf("%s: expected %d orders, got %d", name, count, got[name])
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateCapacity(t *testing.T) {
+	payloadData := &config.PayloadData{
+		BasePolyline: config.BasePolyline{Coordinates: [][]float64{{0.0005, -0.0005}, {0.0005, -0.0015}}},
+		Delta:        config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+		Boundary: config.PolygonBoundary{Coordinates: [][][]float64{{
+			{0, 0}, {0.004, 0}, {0.004, -0.004}, {0, -0.004}, {0, 0},
+		}}},
+	}
+
+	// Four columns per row and two rows of height 0.001 plus 0.001 spacing
+	if got := payload.EstimateCapacity(payloadData, 1000); got != 8 {
+		t.Errorf("expected capacity 8, got %d", got)
+	}
+	if got := payload.EstimateCapacity(payloadData, 5); got != 5 {
+		t.Errorf("expected capacity capped at 5, got %d", got)
+	}
+
+	payloadData.Boundary.Coordinates = nil
+	if got := payload.EstimateCapacity(payloadData, 1000); got != payload.Unbounded {
+		t.Errorf("expected unbounded capacity without a boundary, got %d", got)
+	}
+}
+
+func TestGenerateOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy      string
+		shouldError bool
+		reuse       bool
+	}{
+		{policy: "", shouldError: true},
+		{policy: config.OverflowFail, shouldError: true},
+		{policy: config.OverflowWrap},
+		{policy: config.OverflowShrink},
+		{policy: config.OverflowReuse, reuse: true},
+	}
+
+	for _, tt := range tests {
+		t.Run("policy="+tt.policy, func(t *testing.T) {
+			cfg := &config.Config{
+				Simulation: config.SimulationConfig{TotalOrders: 12},
+				Payload:    config.PayloadConfig{OrderNumberPrefix: "ORD-", Overflow: tt.policy},
+			}
+			// The same box as TestEstimateCapacity, which holds 8 polylines per layer
+			payloadData := &config.PayloadData{
+				BasePolyline: config.BasePolyline{Coordinates: [][]float64{{0.0005, -0.0005}, {0.0005, -0.0015}}},
+				Delta:        config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+				Boundary: config.PolygonBoundary{Coordinates: [][][]float64{{
+					{0, 0}, {0.004, 0}, {0.004, -0.004}, {0, -0.004}, {0, 0},
+				}}},
+			}
+
+			payloads, err := payload.NewGenerator(cfg, payloadData).Generate()
+			if tt.shouldError {
+				if !errors.Is(err, payload.ErrBoundaryExhausted) {
+					t.Fatalf("expected ErrBoundaryExhausted, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(payloads) != cfg.Simulation.TotalOrders {
+				t.Fatalf("expected %d payloads, got %d", cfg.Simulation.TotalOrders, len(payloads))
+			}
+
+			starts := make(map[[2]float64]int)
+			for i, p := range payloads {
+				start := [2]float64{p.Geometry.Coordinates[0][0], p.Geometry.Coordinates[0][1]}
+				if prev, seen := starts[start]; seen && !tt.reuse {
+					t.Errorf("payloads %d and %d share start position %v", prev, i, start)
+				}
+				starts[start] = i
+			}
+			if tt.reuse && len(starts) != 8 {
+				t.Errorf("expected reuse to cycle through 8 positions, got %d", len(starts))
+			}
+		})
+	}
+}
+
+func TestGenerateTemplates(t *testing.T) {
+	cfg := &config.Config{
+		Simulation: config.SimulationConfig{TotalOrders: 8},
+		Payload:    config.PayloadConfig{OrderNumberPrefix: "ORD-"},
+	}
+	payloadData := &config.PayloadData{
+		Templates: []config.Template{
+			{Name: "pickup", Weight: 3, Type: config.GeometryPoint, Parts: [][][]float64{{{0.0005, -0.0005}}}},
+			{Name: "area", Type: config.GeometryPolygon, Parts: [][][]float64{{
+				{0.0002, -0.0002}, {0.0008, -0.0002}, {0.0008, -0.0008}, {0.0002, -0.0002},
+			}}},
+		},
+		Delta: config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+		Boundary: config.PolygonBoundary{Coordinates: [][][]float64{{
+			{0, 0}, {0.004, 0}, {0.004, -0.004}, {0, -0.004}, {0, 0},
+		}}},
+	}
+
+	payloads, err := payload.NewGenerator(cfg, payloadData).Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	types := make(map[string]int)
+	for _, p := range payloads {
+		types[p.Geometry.Type]++
+	}
+	if types[config.GeometryPoint] != 6 || types[config.GeometryPolygon] != 2 {
+		t.Errorf("expected 6 points and 2 polygons for weights 3:1, got %v", types)
+	}
+
+	// Each type keeps its GeoJSON coordinate nesting
+	for _, p := range payloads[:4] {
+		data, err := json.Marshal(p.Geometry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded payload.GeoJSONGeometry
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("round trip of %s: %v", data, err)
+		}
+		if decoded.Type != p.Geometry.Type || !reflect.DeepEqual(decoded.Lines(), p.Geometry.Lines()) {
+			t.Errorf("round trip changed %s", data)
+		}
+		if p.Geometry.Type == config.GeometryPoint && !strings.HasPrefix(string(data), `{"type":"Point","coordinates":[0.`) {
+			t.Errorf("expected a single position for a Point, got %s", data)
+		}
+	}
+}
+
+func TestVerifyNonOverlap(t *testing.T) {
+	line := func(order string, coords ...[]float64) payload.OrderPayload {
+		return payload.OrderPayload{OrderNumber: order, Geometry: &payload.GeoJSONGeometry{Type: config.GeometryLineString, Coordinates: coords}}
+	}
+	payloads := []payload.OrderPayload{
+		line("A", []float64{0, 0}, []float64{2, 2}),
+		line("B", []float64{0, 2}, []float64{2, 0}),
+		line("C", []float64{5, 0}, []float64{5, 2}),
+		{OrderNumber: "D", Geometry: &payload.GeoJSONGeometry{Type: config.GeometryPolygon, Parts: [][][]float64{{
+			{10, 0}, {14, 0}, {14, 4}, {10, 4}, {10, 0},
+		}}}},
+		{OrderNumber: "E", Geometry: &payload.GeoJSONGeometry{Type: config.GeometryPoint, Coordinates: [][]float64{{12, 2}}}},
+		line("F", []float64{20, 0}, []float64{20, 2}),
+	}
+
+	got := payload.VerifyNonOverlap(payloads)
+	want := []payload.Overlap{{First: "A", Second: "B"}, {First: "D", Second: "E"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VerifyNonOverlap() = %v, expected %v", got, want)
+	}
+}
+
+func TestGenerateStrictVerify(t *testing.T) {
+	for _, mode := range []string{config.VerifyReport, config.VerifyStrict} {
+		t.Run(mode, func(t *testing.T) {
+			cfg := &config.Config{
+				Simulation: config.SimulationConfig{TotalOrders: 3},
+				Payload:    config.PayloadConfig{OrderNumberPrefix: "ORD-", Verify: mode},
+			}
+			// Squares twice as wide as the column spacing overlap their neighbours
+			payloadData := &config.PayloadData{
+				Templates: []config.Template{{Name: "wide", Type: config.GeometryPolygon, Parts: [][][]float64{{
+					{0, 0}, {0.002, 0}, {0.002, -0.001}, {0, -0.001}, {0, 0},
+				}}}},
+				Delta: config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+			}
+
+			_, err := payload.NewGenerator(cfg, payloadData).Generate()
+			if mode == config.VerifyStrict && !errors.Is(err, payload.ErrOverlap) {
+				t.Errorf("expected ErrOverlap in strict mode, got %v", err)
+			}
+			if mode == config.VerifyReport && err != nil {
+				t.Errorf("expected overlaps only to be reported, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPlacementStrategies(t *testing.T) {
+	box := config.PolygonBoundary{Coordinates: [][][]float64{{
+		{0, 0}, {0.02, 0}, {0.02, -0.02}, {0, -0.02}, {0, 0},
+	}}}
+	route := [][]float64{{0.001, -0.001}, {0.019, -0.001}, {0.019, -0.018}, {0.001, -0.018}}
+
+	for _, strategy := range []string{config.PlacementZigzag, config.PlacementHex, config.PlacementRandom, config.PlacementPoisson, config.PlacementRoute} {
+		t.Run(strategy, func(t *testing.T) {
+			generate := func() []payload.OrderPayload {
+				cfg := &config.Config{
+					Simulation: config.SimulationConfig{TotalOrders: 30, Seed: 42},
+					Payload: config.PayloadConfig{
+						OrderNumberPrefix: "ORD-",
+						Placement:         config.PlacementConfig{Strategy: strategy, MinSeparation: 0.0005},
+					},
+				}
+				payloadData := &config.PayloadData{
+					BasePolyline: config.BasePolyline{Coordinates: [][]float64{{0.0005, -0.0005}, {0.0008, -0.0015}}},
+					Delta:        config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+					Boundary:     box,
+					Route:        route,
+				}
+				payloads, err := payload.NewGenerator(cfg, payloadData).Generate()
+				if err != nil {
+					t.Fatalf("Generate: %v", err)
+				}
+				return payloads
+			}
+
+			payloads := generate()
+			if len(payloads) != 30 {
+				t.Fatalf("expected 30 payloads, got %d", len(payloads))
+			}
+			if overlaps := payload.VerifyNonOverlap(payloads); len(overlaps) > 0 {
+				t.Errorf("expected no overlaps, got %v", overlaps)
+			}
+			for i, p := range payloads {
+				for _, c := range p.Geometry.Coordinates {
+					if c[0] <= 0 || c[0] >= 0.02 || c[1] >= 0 || c[1] <= -0.02 {
+						t.Fatalf("payload %d leaves the boundary at %v", i, c)
+					}
+				}
+			}
+
+			if again := generate(); !reflect.DeepEqual(again[29].Geometry, payloads[29].Geometry) {
+				t.Error("expected a seeded run to place orders identically")
+			}
+		})
+	}
+}
+
+func TestPlacementNeedsBoundary(t *testing.T) {
+	cfg := &config.Config{
+		Simulation: config.SimulationConfig{TotalOrders: 5},
+		Payload:    config.PayloadConfig{Placement: config.PlacementConfig{Strategy: config.PlacementRandom}},
+	}
+	payloadData := &config.PayloadData{
+		BasePolyline: config.BasePolyline{Coordinates: [][]float64{{0, 0}, {0, -0.001}}},
+		Delta:        config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+	}
+
+	if _, err := payload.NewGenerator(cfg, payloadData).Generate(); !errors.Is(err, payload.ErrBoundaryExhausted) {
+		t.Errorf("expected ErrBoundaryExhausted without a boundary, got %v", err)
+	}
+}
+
+func TestMetricSpacing(t *testing.T) {
+	// Haversine distance in meters
+	groundDistance := func(a, b []float64) float64 {
+		const r = 6371008.8
+		rad := math.Pi / 180
+		dLat, dLng := (b[1]-a[1])*rad, (b[0]-a[0])*rad
+		h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(a[1]*rad)*math.Cos(b[1]*rad)*math.Pow(math.Sin(dLng/2), 2)
+		return 2 * r * math.Asin(math.Sqrt(h))
+	}
+
+	cities := map[string][]float64{"dallas": {-96.80, 32.79}, "oslo": {10.75, 59.91}}
+	for _, projection := range []string{config.ProjectionEquirectangular, config.ProjectionUTM} {
+		for name, origin := range cities {
+			t.Run(projection+"/"+name, func(t *testing.T) {
+				cfg := &config.Config{
+					Simulation: config.SimulationConfig{TotalOrders: 2},
+					Payload:    config.PayloadConfig{OrderNumberPrefix: "ORD-"},
+				}
+				payloadData := &config.PayloadData{
+					BasePolyline: config.BasePolyline{Coordinates: [][]float64{origin, {origin[0], origin[1] - 0.001}}},
+					Delta:        config.CoordinateDelta{Longitude: 100, Latitude: 50, Unit: config.UnitMeters, Projection: projection},
+				}
+
+				payloads, err := payload.NewGenerator(cfg, payloadData).Generate()
+				if err != nil {
+					t.Fatalf("Generate: %v", err)
+				}
+
+				got := groundDistance(payloads[0].Geometry.Coordinates[0], payloads[1].Geometry.Coordinates[0])
+				if math.Abs(got-100) > 0.5 {
+					t.Errorf("expected neighbouring columns 100m apart, got %.2fm", got)
+				}
+			})
+		}
+	}
+}
+
+func TestPathSources(t *testing.T) {
+	box := config.PolygonBoundary{Coordinates: [][][]float64{{
+		{0, 0}, {0.02, 0}, {0.02, -0.02}, {0, -0.02}, {0, 0},
+	}}}
+
+	// A 5x5 road grid inside the boundary
+	graph := &config.RoadGraph{Nodes: make(map[string][]float64)}
+	for row := 0; row < 5; row++ {
+		for col := 0; col < 5; col++ {
+			id := fmt.Sprintf("%d-%d", row, col)
+			graph.Nodes[id] = []float64{0.002 + float64(col)*0.004, -0.002 - float64(row)*0.004}
+			if col > 0 {
+				graph.Edges = append(graph.Edges, config.RoadEdge{From: fmt.Sprintf("%d-%d", row, col-1), To: id})
+			}
+			if row > 0 {
+				graph.Edges = append(graph.Edges, config.RoadEdge{From: fmt.Sprintf("%d-%d", row-1, col), To: id})
+			}
+		}
+	}
+
+	// Five routes, one of them leaving the boundary
+	var routes [][][]float64
+	for i := 0; i < 5; i++ {
+		y := -0.002 - float64(i)*0.003
+		routes = append(routes, [][]float64{{0.001, y}, {0.010, y - 0.001}, {0.019, y}})
+	}
+	routes[2][1][1] = 0.01
+
+	generate := func(source *config.PathSource, total int) ([]payload.OrderPayload, error) {
+		cfg := &config.Config{
+			Simulation: config.SimulationConfig{TotalOrders: total, Seed: 42},
+			Payload:    config.PayloadConfig{OrderNumberPrefix: "ORD-", Verify: config.VerifyOff},
+		}
+		payloadData := &config.PayloadData{
+			Delta:    config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+			Boundary: box,
+			Paths:    source,
+		}
+		return payload.NewGenerator(cfg, payloadData).Generate()
+	}
+
+	t.Run("graph", func(t *testing.T) {
+		payloads, err := generate(&config.PathSource{Format: config.PathsGraph, Graph: graph}, 30)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		seen := make(map[string]bool)
+		for i, p := range payloads {
+			line := p.Geometry.Coordinates
+			if p.Geometry.Type != config.GeometryLineString || len(line) < 2 {
+				t.Fatalf("payload %d: expected a route, got %+v", i, p.Geometry)
+			}
+			for k := 1; k < len(line); k++ {
+				dx, dy := math.Abs(line[k][0]-line[k-1][0]), math.Abs(line[k][1]-line[k-1][1])
+				if math.Abs(dx+dy-0.004) > 1e-9 || (dx > 1e-9 && dy > 1e-9) {
+					t.Fatalf("payload %d: step %v to %v is not a road", i, line[k-1], line[k])
+				}
+			}
+			key := fmt.Sprint(line)
+			if seen[key] {
+				t.Errorf("payload %d repeats route %s", i, key)
+			}
+			seen[key] = true
+		}
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		source := &config.PathSource{Format: config.PathsGeoJSON, Routes: routes}
+		payloads, err := generate(source, 4)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		for i, p := range payloads {
+			if reflect.DeepEqual(p.Geometry.Coordinates, routes[2]) {
+				t.Errorf("payload %d uses the route outside the boundary", i)
+			}
+		}
+
+		if _, err := generate(source, 5); !errors.Is(err, payload.ErrBoundaryExhausted) {
+			t.Errorf("expected ErrBoundaryExhausted with more orders than routes, got %v", err)
+		}
+	})
+}
+
+func TestGenerateTiming(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	generate := func(encoding string) []payload.OrderPayload {
+		cfg := &config.Config{
+			Simulation: config.SimulationConfig{TotalOrders: 10, Seed: 7, Epoch: epoch},
+			Payload: config.PayloadConfig{
+				OrderNumberPrefix: "ORD-",
+				Timing: config.TimingConfig{
+					Encoding:  encoding,
+					Speed:     config.SpeedProfile{Cruise: 10, Acceleration: 1, Jitter: 0.2},
+					Departure: config.Interval{Distribution: config.DistributionUniform, Max: time.Hour},
+				},
+			},
+		}
+		payloadData := &config.PayloadData{
+			BasePolyline: config.BasePolyline{Coordinates: [][]float64{{0, 0}, {0.001, -0.001}, {0.001, -0.003}}},
+			Delta:        config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+		}
+		payloads, err := payload.NewGenerator(cfg, payloadData).Generate()
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		return payloads
+	}
+
+	for _, p := range generate(config.TimingCoordTimes) {
+		if p.Departure == nil || p.Departure.Before(epoch) || p.Departure.After(epoch.Add(time.Hour)) {
+			t.Fatalf("%s: departure %v outside the hour after the epoch", p.OrderNumber, p.Departure)
+		}
+		if len(p.CoordTimes) != 1 || len(p.CoordTimes[0]) != 3 {
+			t.Fatalf("%s: expected one time per position, got %v", p.OrderNumber, p.CoordTimes)
+		}
+		times := p.CoordTimes[0]
+		if !times[0].Equal(*p.Departure) || !times[1].After(times[0]) || !times[2].After(times[1]) {
+			t.Errorf("%s: expected times rising from the departure, got %v", p.OrderNumber, times)
+		}
+		// About 360 m at up to 12 m/s, plus ramps
+		if trip := times[2].Sub(times[0]); trip < 30*time.Second || trip > 60*time.Second {
+			t.Errorf("%s: unexpected trip time %s", p.OrderNumber, trip)
+		}
+	}
+
+	for _, p := range generate(config.TimingM) {
+		if p.CoordTimes != nil {
+			t.Errorf("%s: expected no coordTimes with M values", p.OrderNumber)
+		}
+		line := p.Geometry.Coordinates
+		for i, position := range line {
+			if len(position) != 4 || position[2] != 0 {
+				t.Fatalf("%s: expected [lng, lat, 0, m], got %v", p.OrderNumber, position)
+			}
+			if i > 0 && position[3] <= line[i-1][3] {
+				t.Errorf("%s: M values do not rise: %v", p.OrderNumber, line)
+			}
+		}
+		if line[0][3] != float64(p.Departure.UnixMilli())/1000 {
+			t.Errorf("%s: expected the first M value at the departure, got %v", p.OrderNumber, line[0][3])
+		}
+	}
+}
+
+func TestInjectFaults(t *testing.T) {
+	cfg := &config.Config{
+		Simulation: config.SimulationConfig{TotalOrders: 12, Seed: 3},
+		Payload: config.PayloadConfig{
+			OrderNumberPrefix: "ORD-",
+			Verify:            config.VerifyStrict,
+			Faults: config.FaultConfig{
+				Fraction: 0.5,
+				Expect:   map[string]string{config.FaultAntimeridian: config.OutcomeRejected},
+			},
+		},
+	}
+	payloadData := &config.PayloadData{
+		BasePolyline: config.BasePolyline{Coordinates: [][]float64{{0.0005, -0.0005}, {0.0008, -0.0015}}},
+		Delta:        config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+		Boundary: config.PolygonBoundary{Coordinates: [][][]float64{{
+			{0, 0}, {0.02, 0}, {0.02, -0.02}, {0, -0.02}, {0, 0},
+		}}},
+	}
+
+	// Strict verification passes: faults go in after the layout is checked
+	payloads, err := payload.NewGenerator(cfg, payloadData).Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	faults := make(map[string]payload.OrderPayload)
+	for _, p := range payloads {
+		if p.Fault != "" {
+			faults[p.Fault] = p
+		}
+	}
+	if len(faults) != len(config.FaultKinds) {
+		t.Fatalf("expected one order of each of %d kinds, got %v", len(config.FaultKinds), faults)
+	}
+
+	for kind, p := range faults {
+		line := p.Geometry.Lines()[0]
+		want := config.DefaultFaultOutcomes[kind]
+		if kind == config.FaultAntimeridian {
+			want = config.OutcomeRejected
+		}
+		if p.ExpectedOutcome != want {
+			t.Errorf("%s: expected outcome %q, got %q", kind, want, p.ExpectedOutcome)
+		}
+
+		var ok bool
+		switch kind {
+		case config.FaultOverlap:
+			for _, other := range payloads {
+				ok = ok || (other.Fault == "" && reflect.DeepEqual(other.Geometry, p.Geometry))
+			}
+		case config.FaultBoundary:
+			ok = line[len(line)-1][0] > 0.02
+		case config.FaultSelfIntersect:
+			ok = len(line) == 4
+		case config.FaultDegenerate:
+			ok = len(line) == 1
+		case config.FaultAntimeridian:
+			ok = line[0][0] > 179 && line[1][0] < -179
+		case config.FaultInvalidCoordinates:
+			ok = line[0][1] > 90 || line[0][0] > 180
+		}
+		if !ok {
+			t.Errorf("%s: unexpected geometry %v", kind, p.Geometry)
+		}
+	}
+}
+
+func TestPayloadSetRoundTrip(t *testing.T) {
+	cfg := &config.Config{
+		Simulation: config.SimulationConfig{TotalOrders: 8, ActivatedCount: 3, BatchSize: 3, Seed: 11,
+			Epoch: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Identities: config.IdentityConfig{Pool: []config.Identity{{Name: "alpha"}, {Name: "beta"}}},
+		Payload: config.PayloadConfig{
+			OrderNumberPrefix: "ORD-",
+			Timing:            config.TimingConfig{Encoding: config.TimingCoordTimes, Speed: config.SpeedProfile{Cruise: 10}},
+			Faults:            config.FaultConfig{Fraction: 0.25, Kinds: map[string]int{config.FaultDegenerate: 1}},
+		},
+	}
+	payloadData := &config.PayloadData{
+		BasePolyline: config.BasePolyline{Coordinates: [][]float64{{0.0005, -0.0005}, {0.0008, -0.0015}}},
+		Delta:        config.CoordinateDelta{Longitude: 0.001, Latitude: 0.001},
+		Boundary: config.PolygonBoundary{Coordinates: [][][]float64{{
+			{0, 0}, {0.02, 0}, {0.02, -0.02}, {0, -0.02}, {0, 0},
+		}}},
+	}
+
+	generator := payload.NewGenerator(cfg, payloadData)
+	payloads, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	set := payload.NewPayloadSet(cfg, generator, payload.NewDistributor(cfg.Simulation.BatchSize).Distribute(payloads))
+
+	path := t.TempDir() + "/sets/payloads.json"
+	if err := set.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"ID"`, `"Payloads"`, `"Name"`, `"Coordinates"`, `"Polygons"`} {
+		if strings.Contains(string(data), key) {
+			t.Errorf("payload set file has untagged key %s", key)
+		}
+	}
+	for _, key := range []string{`"id"`, `"payloads"`, `"boundaries"`, `"polygons"`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("payload set file is missing key %s", key)
+		}
+	}
+
+	loaded, err := payload.LoadPayloadSet(path)
+	if err != nil {
+		t.Fatalf("LoadPayloadSet: %v", err)
+	}
+	if boundaries := loaded.BoundarySet(); len(boundaries) != 1 || len(boundaries[0].AllPolygons()) != 1 {
+		t.Errorf("expected the boundary to survive a round trip, got %+v", boundaries)
+	}
+
+	want, _ := json.Marshal(set)
+	got, _ := json.Marshal(loaded)
+	if string(got) != string(want) {
+		t.Errorf("payload set changed in a round trip:\n got %s\nwant %s", got, want)
+	}
+	if len(loaded.Batches) != 3 || len(loaded.Payloads()) != 8 || loaded.Seed != 11 {
+		t.Errorf("expected 8 orders in 3 batches with seed 11, got %d in %d with seed %d",
+			len(loaded.Payloads()), len(loaded.Batches), loaded.Seed)
+	}
+
+	if err := loaded.CheckIdentities(cfg.Identities); err != nil {
+		t.Errorf("CheckIdentities: %v", err)
+	}
+	if err := loaded.CheckIdentities(config.IdentityConfig{Pool: []config.Identity{{Name: "alpha"}}}); err == nil || !strings.Contains(err.Error(), "beta") {
+		t.Errorf("expected beta to be reported missing, got %v", err)
+	}
+
+	future := *set
+	future.Version = payload.PayloadSetVersion + 1
+	if err := future.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := payload.LoadPayloadSet(path); !errors.Is(err, payload.ErrPayloadSetVersion) {
+		t.Errorf("expected ErrPayloadSetVersion, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"gameday-sim/internal/config"
+	"gameday-sim/internal/payload"
+	"gameday-sim/internal/reporter"
+	"gameday-sim/internal/simulator"
+)
+
+func TestSaveOutcomeGeoJSON(t *testing.T) {
+	line := func(x float64) *payload.GeoJSONGeometry {
+		return &payload.GeoJSONGeometry{Type: config.GeometryLineString, Coordinates: [][]float64{{x, 0}, {x, -0.001}}}
+	}
+	payloads := []payload.OrderPayload{
+		{OrderNumber: "ORD-1", Type: payload.TypeActivate, Geometry: line(0.001)},
+		{OrderNumber: "ORD-2", Type: payload.TypeAccepted, Geometry: line(0.002), Fault: config.FaultBoundary, ExpectedOutcome: config.OutcomeRejected},
+		{OrderNumber: "ORD-3", Type: payload.TypeAccepted, Geometry: line(0.003)},
+	}
+	result := &simulator.SimulationResult{BatchResults: []simulator.BatchResult{{
+		BatchID: 2,
+		OrderResults: []*simulator.OrderResult{
+			{OrderNumber: "ORD-1", OrderID: "id-1", BatchID: 2, State: payload.StateEnded, Outcome: config.OutcomeAccepted, AcceptanceLatency: 1500 * time.Millisecond},
+			{OrderNumber: "ORD-2", BatchID: 2, State: payload.StateFailed, Outcome: config.OutcomeRejected, Error: errors.New("failed to create order: 400")},
+		},
+	}}}
+	boundaries := []config.PolygonBoundary{{Name: config.DefaultBoundaryName, Coordinates: [][][]float64{
+		{{0, 0}, {0.01, 0}, {0.01, -0.01}, {0, -0.01}, {0, 0}},
+	}}}
+
+	filename, err := reporter.SaveOutcomeGeoJSON(result, payloads, boundaries, t.TempDir(), "test")
+	if err != nil {
+		t.Fatalf("SaveOutcomeGeoJSON: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var collection struct {
+		Features []struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		t.Fatalf("invalid GeoJSON: %v", err)
+	}
+	if len(collection.Features) != 4 {
+		t.Fatalf("expected a boundary and 3 orders, got %d features", len(collection.Features))
+	}
+
+	tests := []struct {
+		orderNumber string
+		want        map[string]interface{}
+	}{
+		{"ORD-1", map[string]interface{}{
+			"orderId": "id-1", "state": "ended", "batchId": 2.0, "acceptanceLatencyMs": 1500.0,
+			"stroke": payload.StateColor(payload.StateEnded),
+		}},
+		{"ORD-2", map[string]interface{}{
+			"state": "failed", "outcome": "rejected", "error": "failed to create order: 400", "fault": config.FaultBoundary,
+			"stroke": payload.StateColor(payload.StateFailed), "stroke-width": 4.0,
+		}},
+		{"ORD-3", map[string]interface{}{
+			"state": "not processed", "stroke": payload.StateColor(""),
+		}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.orderNumber, func(t *testing.T) {
+			properties := collection.Features[i+1].Properties
+			if properties["orderNumber"] != tt.orderNumber {
+				t.Fatalf("feature %d is %v, want %s", i+1, properties["orderNumber"], tt.orderNumber)
+			}
+			for key, want := range tt.want {
+				if properties[key] != want {
+					t.Errorf("%s = %v, want %v", key, properties[key], want)
+				}
+			}
+		})
+	}
+}